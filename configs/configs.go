@@ -2,14 +2,45 @@ package configs
 
 
 type Openstack struct {
+	// AuthURL is the keystone endpoint, Host is derived from it when unset
+	// and only used to build port-based endpoints missing from the catalog.
+	AuthURL               string
 	Host                  string
 	AdminPassword         string
+	// ProjectId scopes the token by id instead of by ProjectName, UserId
+	// authenticates the user by id instead of by UserName and its domain.
+	ProjectId             string
 	ProjectName           string
+	UserId                string
 	UserName              string
 	UserPassword          string
 	ExternalNetwork       string
 	ImageId               string
 	FlavorId              string
+	// KeyName is the keypair of the servers the default helpers create,
+	// they refuse to boot a server without one.
+	KeyName               string
+	// Region and Interface select endpoints from the keystone catalog,
+	// Interface is one of public, internal or admin and defaults to public.
+	Region                string
+	Interface             string
+	// CACert is a PEM bundle the https endpoints are verified against
+	// instead of the system roots.
+	CACert                string
+
+	// AuthType selects the keystone auth plugin: password (default),
+	// v3applicationcredential or token.
+	AuthType                    string
+	UserDomainName              string
+	ProjectDomainName           string
+	Token                       string
+	ApplicationCredentialId     string
+	ApplicationCredentialName   string
+	ApplicationCredentialSecret string
+	// DomainName requests a domain-scoped token and SystemScope, set to
+	// "all", a system-scoped one, instead of scoping to ProjectName.
+	DomainName                  string
+	SystemScope                 string
 }
 
 type SDN struct {
@@ -0,0 +1,131 @@
+package configs
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultProfile names the profile of a legacy openstack.yaml.
+	DefaultProfile = "default"
+	// EnvProfile names the profile built from OS_* variables alone.
+	EnvProfile = "envvars"
+)
+
+// Registry holds named configuration profiles, one per cloud, so a process
+// can talk to several clouds and SDN controllers at once.
+type Registry struct {
+	mu          sync.RWMutex
+	profiles    map[string]*Server
+	defaultName string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{profiles: make(map[string]*Server)}
+}
+
+// LoadRegistry loads every profile of the config file found as described in
+// Load. OS_* overrides apply to the default profile only, which is the one
+// selected by OS_CLOUD or the only profile of the file.
+func LoadRegistry(path string) (*Registry, error) {
+	file, err := findConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r := NewRegistry()
+	if file == "" {
+		conf := Server{}
+		conf.Openstack.applyEnv()
+		conf.Openstack.setDefaults()
+		if err = r.Add(EnvProfile, &conf); err != nil {
+			return nil, err
+		}
+		r.defaultName = EnvProfile
+		return r, nil
+	}
+
+	profiles, err := readProfiles(file)
+	if err != nil {
+		return nil, err
+	}
+	// without OS_CLOUD a file of several clouds simply has no default, but a
+	// cloud asked for by name must exist
+	cloudName := os.Getenv("OS_CLOUD")
+	name, err := selectProfile(file, profiles, cloudName)
+	switch {
+	case err == nil:
+		r.defaultName = name
+	case cloudName != "":
+		return nil, err
+	}
+
+	problems := make([]string, 0)
+	for name, profile := range profiles {
+		conf := profile
+		if name == r.defaultName {
+			conf.Openstack.applyEnv()
+		}
+		conf.Openstack.setDefaults()
+		if err = r.Add(name, &conf); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) != 0 {
+		return nil, fmt.Errorf("%s: %s", file, strings.Join(problems, "; "))
+	}
+	return r, nil
+}
+
+// Add validates conf and registers it under name.
+func (r *Registry) Add(name string, conf *Server) error {
+	if err := conf.Validate(); err != nil {
+		return fmt.Errorf("profile %q: %w", name, err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.profiles[name] = conf
+	return nil
+}
+
+// Get returns the named profile, or the default profile for an empty name.
+func (r *Registry) Get(name string) (*Server, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if name == "" {
+		if r.defaultName == "" {
+			return nil, fmt.Errorf("no default profile, set OS_CLOUD or pick one of %s",
+				strings.Join(r.names(), ", "))
+		}
+		name = r.defaultName
+	}
+	conf, ok := r.profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found, available profiles: %s", name, strings.Join(r.names(), ", "))
+	}
+	return conf, nil
+}
+
+// SDN returns the SDN controller section of the named profile.
+func (r *Registry) SDN(name string) (*SDN, error) {
+	conf, err := r.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return &conf.SDN, nil
+}
+
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.names()
+}
+
+func (r *Registry) names() []string {
+	profiles := make(map[string]Server, len(r.profiles))
+	for name := range r.profiles {
+		profiles[name] = Server{}
+	}
+	return profileNames(profiles)
+}
@@ -1,33 +1,305 @@
 package configs
 
 import (
+	"crypto/x509"
 	"fmt"
 	"github.com/spf13/viper"
-	"path"
-	"runtime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 )
 
-func Viper() *viper.Viper {
-	v := viper.New()
-	currentPath := getCurrentAbPathByCaller()
-	v.SetConfigFile(currentPath + "\\openstack.yaml")
+// Load reads the configuration and applies OS_* environment overrides. The
+// file is path when given, otherwise the first of $OS_CLIENT_CONFIG_FILE,
+// ./clouds.yaml, ~/.config/openstack/clouds.yaml, /etc/openstack/clouds.yaml
+// and the legacy ./openstack.yaml that exists. Without any file the
+// configuration comes from the environment alone.
+func Load(path string) (*Server, error) {
+	file, err := findConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
 
-	v.SetConfigType("yaml")
-	err := v.ReadInConfig()
+	var conf Server
+	if file != "" {
+		profiles, err := readProfiles(file)
+		if err != nil {
+			return nil, err
+		}
+		name, err := selectProfile(file, profiles, os.Getenv("OS_CLOUD"))
+		if err != nil {
+			return nil, err
+		}
+		conf = profiles[name]
+	}
+	conf.Openstack.applyEnv()
+	conf.Openstack.setDefaults()
+	if err = conf.Validate(); err != nil {
+		return nil, err
+	}
+	return &conf, nil
+}
+
+// Init loads the configuration into CONF.
+func Init(path string) error {
+	conf, err := Load(path)
 	if err != nil {
-		panic(fmt.Errorf("Fail to read yaml err:%v \n", err))
+		return err
+	}
+	CONF = *conf
+	return nil
+}
+
+func configFileCandidates() []string {
+	candidates := make([]string, 0, 5)
+	if file := os.Getenv("OS_CLIENT_CONFIG_FILE"); file != "" {
+		candidates = append(candidates, file)
+	}
+	candidates = append(candidates, "clouds.yaml")
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, ".config", "openstack", "clouds.yaml"))
+	}
+	return append(candidates, filepath.Join("/etc", "openstack", "clouds.yaml"), "openstack.yaml")
+}
+
+func findConfigFile(path string) (string, error) {
+	if path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("config file %s: %w", path, err)
+		}
+		return path, nil
+	}
+	for _, candidate := range configFileCandidates() {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", nil
+}
+
+// readProfiles parses either a clouds.yaml, one profile per cloud, or the
+// legacy layout with top level Openstack and SDN sections as the profile
+// named DefaultProfile.
+func readProfiles(file string) (map[string]Server, error) {
+	v := viper.New()
+	v.SetConfigFile(file)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", file, err)
+	}
+
+	if !v.IsSet("clouds") {
+		var conf Server
+		if err := v.Unmarshal(&conf); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", file, err)
+		}
+		return map[string]Server{DefaultProfile: conf}, nil
+	}
+
+	profiles := make(map[string]Server)
+	for name := range v.GetStringMap("clouds") {
+		var cloud cloudConfig
+		if err := v.Sub("clouds." + name).Unmarshal(&cloud); err != nil {
+			return nil, fmt.Errorf("failed to parse cloud %q in %s: %w", name, file, err)
+		}
+		profiles[name] = cloud.server()
+	}
+	return profiles, nil
+}
+
+// selectProfile returns cloudName, or the only profile when it is empty.
+func selectProfile(file string, profiles map[string]Server, cloudName string) (string, error) {
+	if cloudName == "" {
+		if len(profiles) != 1 {
+			return "", fmt.Errorf("%s defines clouds %s, set OS_CLOUD to select one",
+				file, strings.Join(profileNames(profiles), ", "))
+		}
+		for name := range profiles {
+			cloudName = name
+		}
+	}
+	if _, ok := profiles[cloudName]; !ok {
+		return "", fmt.Errorf("cloud %q not found in %s, available clouds: %s",
+			cloudName, file, strings.Join(profileNames(profiles), ", "))
 	}
-	if err = v.Unmarshal(&CONF); err != nil {
-		fmt.Println(err)
+	return cloudName, nil
+}
+
+func profileNames(profiles map[string]Server) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type cloudAuth struct {
+	AuthURL                     string `mapstructure:"auth_url"`
+	Username                    string `mapstructure:"username"`
+	Password                    string `mapstructure:"password"`
+	UserId                      string `mapstructure:"user_id"`
+	ProjectId                   string `mapstructure:"project_id"`
+	ProjectName                 string `mapstructure:"project_name"`
+	UserDomainName              string `mapstructure:"user_domain_name"`
+	ProjectDomainName           string `mapstructure:"project_domain_name"`
+	DomainName                  string `mapstructure:"domain_name"`
+	SystemScope                 string `mapstructure:"system_scope"`
+	Token                       string `mapstructure:"token"`
+	ApplicationCredentialId     string `mapstructure:"application_credential_id"`
+	ApplicationCredentialName   string `mapstructure:"application_credential_name"`
+	ApplicationCredentialSecret string `mapstructure:"application_credential_secret"`
+}
+
+type cloudSDN struct {
+	Host     string `mapstructure:"host"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+// cloudConfig is one entry of clouds.yaml, the keys after interface are
+// extensions used by this client.
+type cloudConfig struct {
+	Auth            cloudAuth `mapstructure:"auth"`
+	AuthType        string    `mapstructure:"auth_type"`
+	RegionName      string    `mapstructure:"region_name"`
+	Interface       string    `mapstructure:"interface"`
+	CACert          string    `mapstructure:"cacert"`
+	AdminPassword   string    `mapstructure:"admin_password"`
+	ExternalNetwork string    `mapstructure:"external_network"`
+	ImageId         string    `mapstructure:"image_id"`
+	FlavorId        string    `mapstructure:"flavor_id"`
+	KeyName         string    `mapstructure:"key_name"`
+	SDN             cloudSDN  `mapstructure:"sdn"`
+}
+
+func (c cloudConfig) server() Server {
+	return Server{
+		Openstack: Openstack{
+			AuthURL: c.Auth.AuthURL,
+			AdminPassword: c.AdminPassword,
+			ProjectId: c.Auth.ProjectId,
+			ProjectName: c.Auth.ProjectName,
+			UserId: c.Auth.UserId,
+			UserName: c.Auth.Username,
+			UserPassword: c.Auth.Password,
+			ExternalNetwork: c.ExternalNetwork,
+			ImageId: c.ImageId,
+			FlavorId: c.FlavorId,
+			KeyName: c.KeyName,
+			Region: c.RegionName,
+			Interface: c.Interface,
+			CACert: c.CACert,
+			AuthType: c.AuthType,
+			UserDomainName: c.Auth.UserDomainName,
+			ProjectDomainName: c.Auth.ProjectDomainName,
+			Token: c.Auth.Token,
+			ApplicationCredentialId: c.Auth.ApplicationCredentialId,
+			ApplicationCredentialName: c.Auth.ApplicationCredentialName,
+			ApplicationCredentialSecret: c.Auth.ApplicationCredentialSecret,
+			DomainName: c.Auth.DomainName,
+			SystemScope: c.Auth.SystemScope,
+		},
+		SDN: SDN{
+			SDNHost: c.SDN.Host,
+			SDNUserName: c.SDN.Username,
+			SDNPassword: c.SDN.Password,
+		},
 	}
-	return v
 }
 
-func getCurrentAbPathByCaller() string {
-	var abPath string
-	_, filename, _, ok := runtime.Caller(0)
-	if ok {
-		abPath = path.Dir(filename)
+// applyEnv overrides the file settings with the standard OS_* variables.
+func (o *Openstack) applyEnv() {
+	for env, field := range map[string]*string{
+		"OS_AUTH_URL": &o.AuthURL,
+		"OS_AUTH_TYPE": &o.AuthType,
+		"OS_USER_ID": &o.UserId,
+		"OS_USERNAME": &o.UserName,
+		"OS_PASSWORD": &o.UserPassword,
+		"OS_PROJECT_ID": &o.ProjectId,
+		"OS_PROJECT_NAME": &o.ProjectName,
+		"OS_USER_DOMAIN_NAME": &o.UserDomainName,
+		"OS_PROJECT_DOMAIN_NAME": &o.ProjectDomainName,
+		"OS_DOMAIN_NAME": &o.DomainName,
+		"OS_SYSTEM_SCOPE": &o.SystemScope,
+		"OS_TOKEN": &o.Token,
+		"OS_APPLICATION_CREDENTIAL_ID": &o.ApplicationCredentialId,
+		"OS_APPLICATION_CREDENTIAL_NAME": &o.ApplicationCredentialName,
+		"OS_APPLICATION_CREDENTIAL_SECRET": &o.ApplicationCredentialSecret,
+		"OS_REGION_NAME": &o.Region,
+		"OS_INTERFACE": &o.Interface,
+		"OS_CACERT": &o.CACert,
+	} {
+		if value, ok := os.LookupEnv(env); ok {
+			*field = value
+		}
 	}
-	return abPath
-}
\ No newline at end of file
+}
+
+// setDefaults derives Host from AuthURL for the port-based fallback endpoints.
+func (o *Openstack) setDefaults() {
+	if o.Host == "" && o.AuthURL != "" {
+		if u, err := url.Parse(o.AuthURL); err == nil {
+			o.Host = u.Hostname()
+		}
+	}
+}
+
+// Validate reports every missing or inconsistent setting at once.
+func (s *Server) Validate() error {
+	o := s.Openstack
+	problems := make([]string, 0)
+	if o.AuthURL == "" && o.Host == "" {
+		problems = append(problems, "one of auth_url (OS_AUTH_URL) or Host is required")
+	}
+	if o.AuthURL != "" {
+		if u, err := url.Parse(o.AuthURL); err != nil || u.Scheme == "" || u.Host == "" {
+			problems = append(problems, fmt.Sprintf("auth_url %q is not an absolute url", o.AuthURL))
+		}
+	}
+	switch o.AuthType {
+	case "", "password":
+		if o.UserName == "" && o.UserId == "" {
+			problems = append(problems, "username (OS_USERNAME) or user_id (OS_USER_ID) is required for password auth")
+		}
+		if o.UserPassword == "" {
+			problems = append(problems, "password (OS_PASSWORD) is required for password auth")
+		}
+	case "token":
+		if o.Token == "" {
+			problems = append(problems, "token (OS_TOKEN) is required for token auth")
+		}
+	case "v3applicationcredential":
+		if o.ApplicationCredentialSecret == "" {
+			problems = append(problems, "application_credential_secret is required for application credential auth")
+		}
+		if o.ApplicationCredentialId == "" && (o.ApplicationCredentialName == "" || (o.UserName == "" && o.UserId == "")) {
+			problems = append(problems, "application_credential_id, or application_credential_name with username or user_id, "+
+				"is required for application credential auth")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("unsupported auth_type %q", o.AuthType))
+	}
+	switch o.Interface {
+	case "", "public", "internal", "admin":
+	default:
+		problems = append(problems, fmt.Sprintf("interface %q is not one of public, internal or admin", o.Interface))
+	}
+	if o.SystemScope != "" && o.SystemScope != "all" {
+		problems = append(problems, fmt.Sprintf("system_scope %q is not supported, use all", o.SystemScope))
+	}
+	if o.CACert != "" {
+		if pem, err := os.ReadFile(o.CACert); err != nil {
+			problems = append(problems, fmt.Sprintf("cacert (OS_CACERT): %v", err))
+		} else if !x509.NewCertPool().AppendCertsFromPEM(pem) {
+			problems = append(problems, fmt.Sprintf("cacert %s holds no PEM certificate", o.CACert))
+		}
+	}
+
+	if len(problems) != 0 {
+		return fmt.Errorf("invalid openstack config: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package configs
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testClouds = `clouds:
+  first:
+    auth:
+      auth_url: http://file:5000/v3
+      username: file-user
+      password: file-password
+      project_name: file-project
+      user_domain_name: file-domain
+    region_name: RegionOne
+    image_id: file-image
+  second:
+    auth_type: token
+    auth:
+      auth_url: https://second:5000/v3
+      token: file-token
+    interface: internal
+`
+
+func TestValidate(t *testing.T) {
+	caCert := writeCACert(t)
+	password := Openstack{AuthURL: "http://ctl:5000/v3", UserName: "u", UserPassword: "p"}
+	tests := []struct {
+		name      string
+		modify    func(o *Openstack)
+		problems  []string
+	}{
+		{"password", func(o *Openstack) {}, nil},
+		{"password with user id", func(o *Openstack) { o.UserName, o.UserId = "", "u1" }, nil},
+		{"host only", func(o *Openstack) { o.AuthURL, o.Host = "", "ctl" }, nil},
+		{"no endpoint", func(o *Openstack) { o.AuthURL = "" }, []string{"auth_url (OS_AUTH_URL) or Host"}},
+		{"relative auth url", func(o *Openstack) { o.AuthURL = "ctl:5000/v3" }, []string{"not an absolute url"}},
+		{"no user", func(o *Openstack) { o.UserName = "" }, []string{"username (OS_USERNAME) or user_id"}},
+		{"no password", func(o *Openstack) { o.UserPassword = "" }, []string{"password (OS_PASSWORD)"}},
+		{"token", func(o *Openstack) { o.AuthType, o.Token = "token", "t" }, nil},
+		{"no token", func(o *Openstack) { o.AuthType = "token" }, []string{"token (OS_TOKEN)"}},
+		{"application credential id", func(o *Openstack) {
+			o.AuthType, o.ApplicationCredentialId, o.ApplicationCredentialSecret = "v3applicationcredential", "ac", "s"
+		}, nil},
+		{"application credential name without user", func(o *Openstack) {
+			o.AuthType, o.UserName = "v3applicationcredential", ""
+			o.ApplicationCredentialName, o.ApplicationCredentialSecret = "ac", "s"
+		}, []string{"application_credential_name with username or user_id"}},
+		{"application credential without secret", func(o *Openstack) {
+			o.AuthType, o.ApplicationCredentialId = "v3applicationcredential", "ac"
+		}, []string{"application_credential_secret"}},
+		{"unknown auth type", func(o *Openstack) { o.AuthType = "kerberos" }, []string{`auth_type "kerberos"`}},
+		{"unknown interface", func(o *Openstack) { o.Interface = "private" }, []string{`interface "private"`}},
+		{"project system scope", func(o *Openstack) { o.SystemScope = "project" }, []string{`system_scope "project"`}},
+		{"cacert", func(o *Openstack) { o.CACert = caCert }, nil},
+		{"missing cacert", func(o *Openstack) { o.CACert = filepath.Join(t.TempDir(), "ca.pem") }, []string{"cacert (OS_CACERT)"}},
+		{"every problem at once", func(o *Openstack) {
+			o.AuthURL, o.UserName, o.UserPassword, o.Interface = "", "", "", "private"
+		}, []string{"auth_url (OS_AUTH_URL)", "username (OS_USERNAME)", "password (OS_PASSWORD)", `interface "private"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := Server{Openstack: password}
+			tt.modify(&conf.Openstack)
+			err := conf.Validate()
+			if len(tt.problems) == 0 {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() = nil, want %v", tt.problems)
+			}
+			for _, problem := range tt.problems {
+				if !strings.Contains(err.Error(), problem) {
+					t.Errorf("Validate() = %v, want it to mention %q", err, problem)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadOverrideOrder(t *testing.T) {
+	file := writeFile(t, "clouds.yaml", testClouds)
+	caCert := writeCACert(t)
+	tests := []struct {
+		name   string
+		env    map[string]string
+		check  func(t *testing.T, o Openstack)
+	}{
+		{
+			name: "file",
+			env: map[string]string{"OS_CLOUD": "first"},
+			check: func(t *testing.T, o Openstack) {
+				expect(t, "AuthURL", o.AuthURL, "http://file:5000/v3")
+				expect(t, "UserName", o.UserName, "file-user")
+				expect(t, "ProjectName", o.ProjectName, "file-project")
+				expect(t, "UserDomainName", o.UserDomainName, "file-domain")
+				expect(t, "Region", o.Region, "RegionOne")
+				expect(t, "ImageId", o.ImageId, "file-image")
+				expect(t, "Host", o.Host, "file")
+			},
+		},
+		{
+			name: "environment over file",
+			env: map[string]string{
+				"OS_CLOUD": "first",
+				"OS_AUTH_URL": "https://env:5000/v3",
+				"OS_USERNAME": "env-user",
+				"OS_PROJECT_NAME": "env-project",
+				"OS_REGION_NAME": "RegionTwo",
+			},
+			check: func(t *testing.T, o Openstack) {
+				expect(t, "AuthURL", o.AuthURL, "https://env:5000/v3")
+				expect(t, "UserName", o.UserName, "env-user")
+				expect(t, "ProjectName", o.ProjectName, "env-project")
+				expect(t, "Region", o.Region, "RegionTwo")
+				expect(t, "UserPassword", o.UserPassword, "file-password")
+				expect(t, "UserDomainName", o.UserDomainName, "file-domain")
+				expect(t, "Host", o.Host, "env")
+			},
+		},
+		{
+			name: "ids and cacert from the environment",
+			env: map[string]string{
+				"OS_CLOUD": "first",
+				"OS_PROJECT_ID": "p1",
+				"OS_USER_ID": "u1",
+				"OS_CACERT": caCert,
+			},
+			check: func(t *testing.T, o Openstack) {
+				expect(t, "ProjectId", o.ProjectId, "p1")
+				expect(t, "UserId", o.UserId, "u1")
+				expect(t, "CACert", o.CACert, caCert)
+			},
+		},
+		{
+			name: "empty variable clears the file value",
+			env: map[string]string{"OS_CLOUD": "first", "OS_REGION_NAME": ""},
+			check: func(t *testing.T, o Openstack) {
+				expect(t, "Region", o.Region, "")
+			},
+		},
+		{
+			name: "other cloud",
+			env: map[string]string{"OS_CLOUD": "second", "OS_INTERFACE": "admin"},
+			check: func(t *testing.T, o Openstack) {
+				expect(t, "AuthType", o.AuthType, "token")
+				expect(t, "Token", o.Token, "file-token")
+				expect(t, "Interface", o.Interface, "admin")
+				expect(t, "ImageId", o.ImageId, "")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for env, value := range tt.env {
+				t.Setenv(env, value)
+			}
+			conf, err := Load(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, conf.Openstack)
+		})
+	}
+}
+
+func TestLoadEnvironmentOnly(t *testing.T) {
+	clearEnv(t)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("OS_AUTH_URL", "http://env:5000/v3")
+	t.Setenv("OS_USER_ID", "u1")
+	t.Setenv("OS_PASSWORD", "env-password")
+	if _, err := os.Stat("/etc/openstack/clouds.yaml"); err == nil {
+		t.Skip("/etc/openstack/clouds.yaml would be read")
+	}
+	conf, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "UserId", conf.UserId, "u1")
+	expect(t, "Host", conf.Host, "env")
+}
+
+func TestLoadSelectsCloud(t *testing.T) {
+	file := writeFile(t, "clouds.yaml", testClouds)
+	clearEnv(t)
+	if _, err := Load(file); err == nil || !strings.Contains(err.Error(), "set OS_CLOUD") {
+		t.Errorf("Load() without OS_CLOUD = %v, want it to ask for OS_CLOUD", err)
+	}
+	t.Setenv("OS_CLOUD", "third")
+	if _, err := Load(file); err == nil || !strings.Contains(err.Error(), "first, second") {
+		t.Errorf("Load() of an unknown cloud = %v, want it to list the clouds", err)
+	}
+	t.Setenv("OS_CLOUD", "first")
+	t.Setenv("OS_PASSWORD", "")
+	if _, err := Load(file); err == nil || !strings.Contains(err.Error(), "password (OS_PASSWORD)") {
+		t.Errorf("Load() with an empty OS_PASSWORD = %v, want a validation error", err)
+	}
+}
+
+func expect(t *testing.T, field, got, want string) {
+	t.Helper()
+	if got != want {
+		t.Errorf("%s = %q, want %q", field, got, want)
+	}
+}
+
+// clearEnv unsets every OS_* variable for the duration of the test.
+func clearEnv(t *testing.T) {
+	for _, env := range os.Environ() {
+		if !strings.HasPrefix(env, "OS_") {
+			continue
+		}
+		name := strings.SplitN(env, "=", 2)[0]
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// writeCACert writes a self-signed certificate as a PEM bundle.
+func writeCACert(t *testing.T) string {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject: pkix.Name{CommonName: "openstack test ca"},
+		NotBefore: time.Now(),
+		NotAfter: time.Now().Add(time.Hour),
+		IsCA: true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, public, private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writeFile(t, "ca.pem", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+}
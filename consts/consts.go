@@ -24,6 +24,10 @@ const (
 
     NOVA                       = "nova"
     CINDER                     = "cinder"
+    NEUTRON                    = "neutron"
+    KEYSTONE                   = "keystone"
+    GLANCE                     = "glance"
+    OCTAVIA                    = "octavia"
 
     VOLUME	                   = "volume"
     VOLUMES	                   = "volumes"
@@ -63,6 +67,30 @@ const (
     SERVER                     = "server"
     SERVERS                    = "servers"
     AGGREGATE                  = "aggregate"
+    AGGREGATES                 = "aggregates"
+    OSAGGREGATES               = "os-aggregates"
+    COMPUTESERVICE             = "service"
+    COMPUTESERVICES            = "services"
+    OSSERVICES                 = "os-services"
+    HYPERVISOR                 = "hypervisor"
+    HYPERVISORS                = "hypervisors"
+    OSHYPERVISORS              = "os-hypervisors"
+    NOVACOMPUTE                = "nova-compute"
+    KEYPAIR                    = "keypair"
+    KEYPAIRS                   = "keypairs"
+    OSKEYPAIRS                 = "os-keypairs"
+    FLAVOR                     = "flavor"
+    FLAVORS                    = "flavors"
+    SERVERGROUP                = "server_group"
+    SERVERGROUPS               = "server_groups"
+    OSSERVERGROUPS             = "os-server-groups"
+    VOLUMEATTACHMENT           = "volumeAttachment"
+    VOLUMEATTACHMENTS          = "volumeAttachments"
+    OSVOLUMEATTACHMENTS        = "os-volume_attachments"
+    INTERFACEATTACHMENT        = "interfaceAttachment"
+    INTERFACEATTACHMENTS       = "interfaceAttachments"
+    OSINTERFACE                = "os-interface"
+    OSVOLUMEUPLOADIMAGE        = "os-volume_upload_image"
     ADDHOST                    = "add_host"
     REMOVEHOST                 = "remove_host"
     SETMETADATA                = "set_metadata"
@@ -71,6 +99,8 @@ const (
     FIREWALLPOLICIES           = "firewall_policies"
     FIREWALLRULES              = "firewall_rules"
     FIREWALL                   = "firewall"
+    FIREWALLS                  = "firewalls"
+    FIREWALLGROUP              = "firewall_group"
     FIREWALLPOLICY             = "firewall_policy"
     FIREWALLRULE               = "firewall_rule"
     PROJECT	                   = "project"
@@ -83,11 +113,24 @@ const (
     SECURITYGROUPRULES         = "security_group_rules"
     SECURITYGROUPRULE          = "security_group_rule"
     RBACPOLICIES               = "rbac_policies"
+    RBACPOLICY                 = "rbac_policy"
+    QUOTA                      = "quota"
+    QUOTAS                     = "quotas"
+    QUOTASET                   = "quota_set"
+    QUOTASETS                  = "os-quota-sets"
+    COMPUTEQUOTA               = "compute_quota"
+    VOLUMEQUOTA                = "volume_quota"
+    ACCESSASSHARED             = "access_as_shared"
+    ACCESSASEXTERNAL           = "access_as_external"
     VPNSERVICE                 = "vpnservice"
     VPNSERVICES                = "vpnservices"
+    ENDPOINTGROUP              = "endpoint_group"
     ENDPOINTGROUPS             = "endpoint_groups"
+    IKEPOLICY                  = "ikepolicy"
     IKEPOLICIES                = "ikepolicies"
+    IPSECPOLICY                = "ipsecpolicy"
     IPSECPOLICIES              = "ipsecpolicies"
+    IPSECCONNECTION            = "ipsec_site_connection"
     IPSECCONNECTIONS           = "ipsec_site_connections"
     LOADBALANCERS              = "loadbalancers"
     LOADBALANCER               = "loadbalancer"
@@ -113,7 +156,15 @@ const (
     Dnat                       = "dnat"
 
     ACTIVE                     = "ACTIVE"
+    SHUTOFF                    = "SHUTOFF"
+    VERIFYRESIZE               = "VERIFY_RESIZE"
+    SHELVED                    = "SHELVED"
+    SHELVEDOFFLOADED           = "SHELVED_OFFLOADED"
+    RESCUE                     = "RESCUE"
+    ERROR                      = "ERROR"
     Available                  = "available"
+    InUse                      = "in-use"
+    DOWN                       = "DOWN"
     Error                      = "error"
 
     KeystonePort               = 5000
@@ -125,10 +176,23 @@ const (
     SDNPort                    = 18002
     SDNMDCPort                 = 31943
 
+    IdentityService            = "identity"
+    NetworkService             = "network"
+    ComputeService             = "compute"
+    VolumeService              = "volumev3"
+    BlockStorageService        = "block-storage"
+    ImageService               = "image"
+    LoadBalancerService        = "load-balancer"
+    PublicInterface            = "public"
+
     ADMIN                      = "admin"
 	AuthToken                  = "X-Auth-Token"
+	OpenStackAPIVersion        = "OpenStack-API-Version"
 	Timeout                    = 2 * 60 * time.Second
 	IntervalTime               = 5 * time.Second
+	MigrationTimeout           = 10 * 60 * time.Second
+	ImageTimeout               = 30 * 60 * time.Second
+	TokenRefreshMargin         = 5 * time.Minute
 
 
     ProtocolAny                = "any"
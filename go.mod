@@ -7,6 +7,7 @@ require (
 	github.com/spf13/viper v1.13.0
 	github.com/valyala/fasthttp v1.45.0
 	go.etcd.io/etcd/client/v3 v3.5.8
+	golang.org/x/crypto v0.12.0
 )
 
 require (
@@ -2,6 +2,10 @@ package client
 
 import (
     "context"
+    "crypto/tls"
+    "crypto/x509"
+    "fmt"
+    "os"
 )
 
 type Request interface {
@@ -18,8 +22,41 @@ type Client interface {
     String()  string
 }
 
-func NewClient() Client {
-    return &RestClient{
+// ClientOption customises a Client built by NewClient.
+type ClientOption func(*RestClient)
+
+// WithRetryPolicy replaces DefaultRetryPolicy, NoRetry disables retries.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+    return func(r *RestClient) {
+        r.retry = policy
+    }
+}
+
+// WithCACertFile verifies https endpoints against the PEM bundle file
+// instead of the system roots. A file that cannot be used fails every Call.
+func WithCACertFile(file string) ClientOption {
+    return func(r *RestClient) {
+        pem, err := os.ReadFile(file)
+        if err != nil {
+            r.err = fmt.Errorf("cacert %s: %w", file, err)
+            return
+        }
+        pool := x509.NewCertPool()
+        if !pool.AppendCertsFromPEM(pem) {
+            r.err = fmt.Errorf("cacert %s holds no PEM certificate", file)
+            return
+        }
+        r.client.TLSConfig = &tls.Config{RootCAs: pool}
+    }
+}
+
+func NewClient(opts ...ClientOption) Client {
+    r := &RestClient{
         client: newRestClient(),
+        retry: DefaultRetryPolicy(),
+    }
+    for _, opt := range opts {
+        opt(r)
     }
+    return r
 }
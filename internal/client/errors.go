@@ -0,0 +1,147 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/valyala/fasthttp"
+	"strings"
+)
+
+// Fault is the error body returned by an OpenStack API, normalised across
+// the NeutronError, compute/volume fault ({"itemNotFound": {...}}), keystone
+// ({"error": {...}}) and octavia ({"faultstring": ...}) formats.
+type Fault struct {
+	Type     string
+	Code     int
+	Message  string
+	Detail   string
+}
+
+// OpenStackError is returned by Call for every non-2xx response.
+type OpenStackError struct {
+	StatusCode    int
+	RequestID     string
+	Service       string
+	Method        string
+	URL           string
+	Body          []byte
+	Fault         Fault
+}
+
+func (e *OpenStackError) Error() string {
+	msg := e.Fault.Message
+	if msg == "" {
+		msg = string(e.Body)
+	}
+	var service string
+	if e.Service != "" {
+		service = e.Service + ": "
+	}
+	if e.Fault.Type != "" {
+		msg = fmt.Sprintf("%s: %s", e.Fault.Type, msg)
+	}
+	return fmt.Sprintf("%s%s %s failed with status %d (request %s): %s",
+		service, e.Method, e.URL, e.StatusCode, e.RequestID, msg)
+}
+
+var requestIDHeaders = []string{"X-Openstack-Request-Id", "X-Compute-Request-Id", "X-Request-Id"}
+
+func newOpenStackError(req Request, res *fasthttp.Response) *OpenStackError {
+	body := append([]byte(nil), res.Body()...)
+	var requestID string
+	for _, header := range requestIDHeaders {
+		if value := res.Header.Peek(header); len(value) != 0 {
+			requestID = string(value)
+			break
+		}
+	}
+	return &OpenStackError{
+		StatusCode: res.StatusCode(),
+		RequestID: requestID,
+		Method: req.Method(),
+		URL: req.Endpoint(),
+		Body: body,
+		Fault: parseFault(body),
+	}
+}
+
+// parseFault decodes the known OpenStack fault envelopes, falling back to the
+// raw body as the message for plain text and HTML error pages.
+func parseFault(body []byte) Fault {
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return Fault{Message: strings.TrimSpace(string(body))}
+	}
+
+	if raw, ok := envelope["NeutronError"]; ok {
+		var neutronError struct {
+			Type     string  `json:"type"`
+			Message  string  `json:"message"`
+			Detail   string  `json:"detail"`
+		}
+		if err := json.Unmarshal(raw, &neutronError); err == nil {
+			return Fault{Type: neutronError.Type, Message: neutronError.Message, Detail: neutronError.Detail}
+		}
+	}
+	if raw, ok := envelope["faultstring"]; ok {
+		var octaviaError struct {
+			Faultcode    string  `json:"faultcode"`
+			Faultstring  string  `json:"faultstring"`
+			Debuginfo    string  `json:"debuginfo"`
+		}
+		if err := json.Unmarshal(body, &octaviaError); err == nil {
+			return Fault{Type: octaviaError.Faultcode, Message: octaviaError.Faultstring, Detail: octaviaError.Debuginfo}
+		}
+		return Fault{Message: string(raw)}
+	}
+	// nova, cinder and keystone wrap the fault in a single object keyed by
+	// its type, e.g. {"badRequest": {"message": "...", "code": 400}}
+	for faultType, raw := range envelope {
+		var fault struct {
+			Message  string  `json:"message"`
+			Code     int     `json:"code"`
+			Title    string  `json:"title"`
+			Details  string  `json:"details"`
+		}
+		if err := json.Unmarshal(raw, &fault); err != nil || fault.Message == "" {
+			continue
+		}
+		if faultType == "error" && fault.Title != "" {
+			faultType = fault.Title
+		}
+		return Fault{Type: faultType, Code: fault.Code, Message: fault.Message, Detail: fault.Details}
+	}
+	return Fault{Message: strings.TrimSpace(string(body))}
+}
+
+// WithService records the service that issued the failed request.
+func WithService(err error, service string) error {
+	var osErr *OpenStackError
+	if errors.As(err, &osErr) {
+		osErr.Service = service
+	}
+	return err
+}
+
+// StatusCode returns the HTTP status carried by err, or 0 when err is not
+// an OpenStackError.
+func StatusCode(err error) int {
+	var osErr *OpenStackError
+	if errors.As(err, &osErr) {
+		return osErr.StatusCode
+	}
+	return 0
+}
+
+func IsNotFound(err error) bool {
+	return StatusCode(err) == fasthttp.StatusNotFound
+}
+
+func IsConflict(err error) bool {
+	return StatusCode(err) == fasthttp.StatusConflict
+}
+
+func IsOverLimit(err error) bool {
+	return StatusCode(err) == fasthttp.StatusRequestEntityTooLarge
+}
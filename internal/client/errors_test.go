@@ -0,0 +1,49 @@
+package client
+
+import "testing"
+
+func TestParseFault(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		want  Fault
+	}{
+		{
+			name: "neutron",
+			body: `{"NeutronError": {"type": "PortNotFound", "message": "Port 42 could not be found.", "detail": ""}}`,
+			want: Fault{Type: "PortNotFound", Message: "Port 42 could not be found."},
+		},
+		{
+			name: "nova",
+			body: `{"itemNotFound": {"code": 404, "message": "Instance 42 could not be found."}}`,
+			want: Fault{Type: "itemNotFound", Code: 404, Message: "Instance 42 could not be found."},
+		},
+		{
+			name: "keystone",
+			body: `{"error": {"code": 401, "title": "Unauthorized", "message": "The request you have made requires authentication."}}`,
+			want: Fault{Type: "Unauthorized", Code: 401, Message: "The request you have made requires authentication."},
+		},
+		{
+			name: "octavia",
+			body: `{"faultcode": "Client", "faultstring": "Load Balancer 42 is immutable.", "debuginfo": null}`,
+			want: Fault{Type: "Client", Message: "Load Balancer 42 is immutable."},
+		},
+		{
+			name: "html",
+			body: "<html><body>502 Bad Gateway</body></html>\n",
+			want: Fault{Message: "<html><body>502 Bad Gateway</body></html>"},
+		},
+		{
+			name: "unknown json",
+			body: `{"status": "error"}`,
+			want: Fault{Message: `{"status": "error"}`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseFault([]byte(tt.body)); got != tt.want {
+				t.Errorf("parseFault() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package client
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"github.com/valyala/fasthttp"
 	"log"
@@ -13,6 +12,9 @@ var _ Client = (*RestClient)(nil)
 
 type RestClient struct {
 	client           *fasthttp.Client
+	retry            RetryPolicy
+	// err is the failure of a ClientOption, returned by every Call
+	err              error
 }
 
 func newRestClient() (client *fasthttp.Client) {
@@ -40,6 +42,9 @@ func (r *RestClient) NewRequest(endpoint, method, contentType string, headers ma
 }
 
 func (r *RestClient) Call(ctx context.Context, req Request, resp interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
 	res := resp.(*fasthttp.Response)
 	request := fasthttp.AcquireRequest()
 	defer fasthttp.ReleaseRequest(request)
@@ -54,21 +59,85 @@ func (r *RestClient) Call(ctx context.Context, req Request, resp interface{}) er
 		request.Header.Set(k, v)
 	}
 
-	log.Printf("Starting to %s request %s, body==%+v", req.Method(), req.Endpoint(), req.Body())
-	err := r.client.Do(request, res)
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		log.Printf("Starting to %s request %s (attempt %d), body==%+v", req.Method(), req.Endpoint(), attempt, req.Body())
+		res.Reset()
+		err := r.attempt(ctx, req, request, res)
+		if err == nil {
+			return nil
+		}
+		wait, ok := r.retry.backoff(req.Method(), attempt, time.Since(start), err, res)
+		if !ok {
+			return err
+		}
+		log.Printf("Attempt %d to %s %s failed, retrying in %s: %s", attempt, req.Method(), req.Endpoint(), wait, err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to make request %s %s: %w", req.Method(), req.Endpoint(), ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+}
+
+// attempt sends request once, turning a non-2xx response into an
+// OpenStackError.
+func (r *RestClient) attempt(ctx context.Context, req Request, request *fasthttp.Request, res *fasthttp.Response) error {
+	err := r.do(ctx, request, res)
 	if err != nil {
-		errorInfo := fmt.Sprintf("Failed to make request %s", err)
-		log.Println(errorInfo)
-		return errors.New(errorInfo)
+		log.Printf("Failed to make request %s %s: %s", req.Method(), req.Endpoint(), err)
+		return fmt.Errorf("failed to make request %s %s: %w", req.Method(), req.Endpoint(), err)
 	}
-	if res.StatusCode() > 204 {
-		errorInfo := fmt.Sprintf("%s request %s failed, resp==%s", req.Method(), req.Endpoint(), res.Body())
-		log.Printf(errorInfo)
-		return errors.New(errorInfo)
+	if res.StatusCode() < fasthttp.StatusOK || res.StatusCode() >= fasthttp.StatusMultipleChoices {
+		osErr := newOpenStackError(req, res)
+		log.Println(osErr)
+		return osErr
 	}
 	return nil
 }
 
+// do sends request honouring ctx: its deadline bounds the exchange and
+// cancelling it returns immediately. fasthttp can not abort an in-flight
+// request, so the exchange runs on copies that are released once it ends.
+func (r *RestClient) do(ctx context.Context, request *fasthttp.Request, res *fasthttp.Response) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if ctx.Done() == nil {
+		return r.client.Do(request, res)
+	}
+
+	req := fasthttp.AcquireRequest()
+	request.CopyTo(req)
+	out := fasthttp.AcquireResponse()
+	release := func() {
+		fasthttp.ReleaseRequest(req)
+		fasthttp.ReleaseResponse(out)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		if deadline, ok := ctx.Deadline(); ok {
+			done <- r.client.DoDeadline(req, out, deadline)
+		} else {
+			done <- r.client.Do(req, out)
+		}
+	}()
+
+	select {
+	case err := <-done:
+		out.CopyTo(res)
+		release()
+		return err
+	case <-ctx.Done():
+		go func() {
+			<-done
+			release()
+		}()
+		return ctx.Err()
+	}
+}
+
 func (r *RestClient) String() string {
     return "rest"
 }
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/valyala/fasthttp"
+	"math"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryRule selects the failures a RetryPolicy retries: a response with one
+// of StatusCodes, or a transport error such as a connection reset when
+// TransportErrors is set, for a request with one of Methods.
+type RetryRule struct {
+	Methods           []string
+	StatusCodes       []int
+	TransportErrors   bool
+}
+
+func (r RetryRule) matches(method string, statusCode int, transportErr bool) bool {
+	if !containsString(r.Methods, method) {
+		return false
+	}
+	if transportErr {
+		return r.TransportErrors
+	}
+	return containsInt(r.StatusCodes, statusCode)
+}
+
+// RetryPolicy retries transient failures with exponential backoff. The n-th
+// retry waits InitialInterval*Multiplier^(n-1), capped at MaxInterval and
+// spread by ±Jitter, or the Retry-After of the response when that is longer.
+// Retrying stops after MaxAttempts attempts or once the next wait would end
+// past MaxElapsedTime; zero disables either limit.
+type RetryPolicy struct {
+	MaxAttempts       int
+	InitialInterval   time.Duration
+	MaxInterval       time.Duration
+	Multiplier        float64
+	Jitter            float64
+	MaxElapsedTime    time.Duration
+	Rules             []RetryRule
+}
+
+// IdempotentMethods are retried by DefaultRetryPolicy, POST and PATCH are
+// not as the first attempt may have been applied.
+var IdempotentMethods = []string{
+	fasthttp.MethodGet, fasthttp.MethodHead, fasthttp.MethodOptions,
+	fasthttp.MethodPut, fasthttp.MethodDelete,
+}
+
+// DefaultRetryPolicy retries idempotent requests on conflicts such as a port
+// still in use or a load balancer in PENDING_UPDATE, on rate limiting, on
+// gateway errors during API restarts and on connection failures.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 5,
+		InitialInterval: 500 * time.Millisecond,
+		MaxInterval: 10 * time.Second,
+		Multiplier: 2,
+		Jitter: 0.2,
+		MaxElapsedTime: time.Minute,
+		Rules: []RetryRule{{
+			Methods: IdempotentMethods,
+			StatusCodes: []int{
+				fasthttp.StatusConflict, fasthttp.StatusTooManyRequests,
+				fasthttp.StatusBadGateway, fasthttp.StatusServiceUnavailable,
+				fasthttp.StatusGatewayTimeout,
+			},
+			TransportErrors: true,
+		}},
+	}
+}
+
+// NoRetry sends every request once.
+func NoRetry() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+var (
+	jitterMu    sync.Mutex
+	jitterRand  = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// backoff returns how long to wait before the attempt following attempt,
+// and false when the failure must not be retried. err is the failure of the
+// attempt and res its response, elapsed the time since the first attempt.
+func (p RetryPolicy) backoff(method string, attempt int, elapsed time.Duration, err error, res *fasthttp.Response) (time.Duration, bool) {
+	if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+		return 0, false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return 0, false
+	}
+	var osErr *OpenStackError
+	transportErr := !errors.As(err, &osErr)
+	statusCode := 0
+	if !transportErr {
+		statusCode = osErr.StatusCode
+	}
+	retryable := false
+	for _, rule := range p.Rules {
+		if rule.matches(method, statusCode, transportErr) {
+			retryable = true
+			break
+		}
+	}
+	if !retryable {
+		return 0, false
+	}
+
+	wait := float64(p.InitialInterval) * math.Pow(math.Max(p.Multiplier, 1), float64(attempt-1))
+	if p.MaxInterval > 0 && wait > float64(p.MaxInterval) {
+		wait = float64(p.MaxInterval)
+	}
+	if p.Jitter > 0 {
+		jitterMu.Lock()
+		wait *= 1 + p.Jitter*(2*jitterRand.Float64()-1)
+		jitterMu.Unlock()
+	}
+	delay := time.Duration(wait)
+	if !transportErr {
+		if retryAfter, ok := parseRetryAfter(res); ok && retryAfter > delay {
+			delay = retryAfter
+		}
+	}
+	if p.MaxElapsedTime > 0 && elapsed+delay > p.MaxElapsedTime {
+		return 0, false
+	}
+	return delay, true
+}
+
+// parseRetryAfter reads the Retry-After header in either its seconds or its
+// HTTP date form.
+func parseRetryAfter(res *fasthttp.Response) (time.Duration, bool) {
+	if res == nil {
+		return 0, false
+	}
+	value := string(res.Header.Peek(fasthttp.HeaderRetryAfter))
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := time.Parse(time.RFC1123, value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"errors"
+	"github.com/valyala/fasthttp"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		min     time.Duration
+		max     time.Duration
+		ok      bool
+	}{
+		{"seconds", "3", 3 * time.Second, 3 * time.Second, true},
+		{"zero seconds", "0", 0, 0, true},
+		{"http date", time.Now().Add(10 * time.Second).UTC().Format(time.RFC1123), 8 * time.Second, 10 * time.Second, true},
+		{"past http date", time.Now().Add(-time.Minute).UTC().Format(time.RFC1123), 0, 0, true},
+		{"negative seconds", "-1", 0, 0, false},
+		{"garbage", "soon", 0, 0, false},
+		{"missing", "", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := fasthttp.AcquireResponse()
+			defer fasthttp.ReleaseResponse(res)
+			if tt.value != "" {
+				res.Header.Set(fasthttp.HeaderRetryAfter, tt.value)
+			}
+			got, ok := parseRetryAfter(res)
+			if ok != tt.ok || got < tt.min || got > tt.max {
+				t.Errorf("parseRetryAfter(%q) = %v, %v, want [%v, %v], %v", tt.value, got, ok, tt.min, tt.max, tt.ok)
+			}
+		})
+	}
+	if _, ok := parseRetryAfter(nil); ok {
+		t.Error("parseRetryAfter(nil) reported a Retry-After")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.Jitter = 0
+	conflict := &OpenStackError{StatusCode: fasthttp.StatusConflict}
+
+	tests := []struct {
+		name      string
+		method    string
+		attempt   int
+		elapsed   time.Duration
+		err       error
+		want      time.Duration
+		retry     bool
+	}{
+		{"first retry", fasthttp.MethodGet, 1, 0, conflict, 500 * time.Millisecond, true},
+		{"second retry", fasthttp.MethodGet, 2, 0, conflict, time.Second, true},
+		{"third retry", fasthttp.MethodGet, 3, 0, conflict, 2 * time.Second, true},
+		{"transport error", fasthttp.MethodDelete, 2, 0, errors.New("connection reset by peer"), time.Second, true},
+		{"past max attempts", fasthttp.MethodGet, 5, 0, conflict, 0, false},
+		{"past max elapsed time", fasthttp.MethodGet, 3, 59 * time.Second, conflict, 0, false},
+		{"within max elapsed time", fasthttp.MethodGet, 3, 58 * time.Second, conflict, 2 * time.Second, true},
+		{"post", fasthttp.MethodPost, 1, 0, conflict, 0, false},
+		{"not found", fasthttp.MethodGet, 1, 0, &OpenStackError{StatusCode: fasthttp.StatusNotFound}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, retry := policy.backoff(tt.method, tt.attempt, tt.elapsed, tt.err, nil)
+			if got != tt.want || retry != tt.retry {
+				t.Errorf("backoff() = %v, %v, want %v, %v", got, retry, tt.want, tt.retry)
+			}
+		})
+	}
+}
+
+func TestBackoffCapsAtMaxInterval(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.Jitter = 0
+	policy.MaxAttempts = 0
+	policy.MaxElapsedTime = 0
+	conflict := &OpenStackError{StatusCode: fasthttp.StatusConflict}
+
+	if got, _ := policy.backoff(fasthttp.MethodGet, 10, 0, conflict, nil); got != policy.MaxInterval {
+		t.Errorf("backoff() = %v, want the max interval %v", got, policy.MaxInterval)
+	}
+}
+
+func TestBackoffHonoursRetryAfter(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.Jitter = 0
+	res := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(res)
+	res.Header.Set(fasthttp.HeaderRetryAfter, "7")
+	tooMany := &OpenStackError{StatusCode: fasthttp.StatusTooManyRequests}
+
+	if got, retry := policy.backoff(fasthttp.MethodGet, 1, 0, tooMany, res); !retry || got != 7*time.Second {
+		t.Errorf("backoff() = %v, %v, want the Retry-After of 7s", got, retry)
+	}
+	if _, retry := policy.backoff(fasthttp.MethodGet, 1, 55*time.Second, tooMany, res); retry {
+		t.Error("backoff() retried although the Retry-After ends past the max elapsed time")
+	}
+}
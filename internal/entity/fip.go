@@ -81,7 +81,9 @@ func (opts *UpdateFipOpts) ToRequestBody() string {
 	return reqBody
 }
 
-func (opts *CreateFipOpts) AssignProps(props map[string]interface{}) (*CreateFipOpts, map[string]string) {
+// AssignProps second output parameter is dependent resources slice, a
+// "local" floating network is the ExternalNetwork of conf
+func (opts *CreateFipOpts) AssignProps(props map[string]interface{}, conf *configs.Server) (*CreateFipOpts, map[string]string) {
 	var deps = make(map[string]string)
 	typ := reflect.TypeOf(opts)
 	val := reflect.ValueOf(opts).Elem()
@@ -95,7 +97,7 @@ func (opts *CreateFipOpts) AssignProps(props map[string]interface{}) (*CreateFip
 			switch fieldType.Kind() {
 			case reflect.String:
 				if compareFieldName == "floating_network_id" && v.(string) == "local"{
-					value.SetString(configs.CONF.ExternalNetwork)
+					value.SetString(conf.ExternalNetwork)
 				} else if compareFieldName == "port_id" && !IsUUID(v.(string)) {
 					deps[v.(string)] = field.Name
 				} else {
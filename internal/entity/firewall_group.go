@@ -1,13 +1,122 @@
 package entity
 
+import (
+	"fmt"
+	"go-openstackclient/consts"
+)
 
 
 type FirewallGroup struct {
-	Ports               []string         `json:"ports"`
+	Id                       string           `json:"id"`
+	Name                     string           `json:"name"`
+	Description              string           `json:"description"`
+	Ports                    []string         `json:"ports"`
+	IngressFirewallPolicyId  string           `json:"ingress_firewall_policy_id"`
+	EgressFirewallPolicyId   string           `json:"egress_firewall_policy_id"`
+	Status                   string           `json:"status"`
+	AdminStateUp             bool             `json:"admin_state_up"`
+	Shared                   bool             `json:"shared"`
+	TenantId                 string           `json:"tenant_id"`
+	ProjectId                string           `json:"project_id"`
 }
 
 type FirewallPolicy struct {
+	Id                    string        `json:"id"`
 	Name                  string        `json:"name"`
+	Description           string        `json:"description"`
 	FirewallRules         []string      `json:"firewall_rules"`
+	Audited               bool          `json:"audited"`
+	Shared                bool          `json:"shared"`
+	TenantId              string        `json:"tenant_id"`
+	ProjectId             string        `json:"project_id"`
 }
 
+type FirewallRule struct {
+	Id                         string        `json:"id"`
+	Name                       string        `json:"name"`
+	Description                string        `json:"description"`
+	Protocol                   interface{}   `json:"protocol"`
+	Action                     string        `json:"action"`
+	IpVersion                  int           `json:"ip_version"`
+	SourceIpAddress            interface{}   `json:"source_ip_address"`
+	DestinationIpAddress       interface{}   `json:"destination_ip_address"`
+	SourcePort                 interface{}   `json:"source_port"`
+	DestinationPort            interface{}   `json:"destination_port"`
+	SourceFirewallGroupId      interface{}   `json:"source_firewall_group_id"`
+	DestinationFirewallGroupId interface{}   `json:"destination_firewall_group_id"`
+	FirewallPolicyId           []string      `json:"firewall_policy_id"`
+	Enabled                    bool          `json:"enabled"`
+	Shared                     bool          `json:"shared"`
+	TenantId                   string        `json:"tenant_id"`
+	ProjectId                  string        `json:"project_id"`
+}
+
+// The v2 policies and rules are created and updated with the v1 options,
+// the bodies are the same.
+
+type CreateFirewallGroupOpts struct {
+	ProjectID               string   `json:"project_id,omitempty"`
+	Name                    string   `json:"name,omitempty"`
+	Description             string   `json:"description,omitempty"`
+	IngressFirewallPolicyID string   `json:"ingress_firewall_policy_id,omitempty"`
+	EgressFirewallPolicyID  string   `json:"egress_firewall_policy_id,omitempty"`
+	Ports                   []string `json:"ports,omitempty"`
+	AdminStateUp            *bool    `json:"admin_state_up,omitempty"`
+	Shared                  *bool    `json:"shared,omitempty"`
+}
+
+func (opts *CreateFirewallGroupOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.FIREWALLGROUP)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+// UpdateFirewallGroupOpts sets Ports when it is not nil, a pointer to an
+// empty slice unbinds every port. The policies are unset with empty strings.
+type UpdateFirewallGroupOpts struct {
+	Name                    *string   `json:"name,omitempty"`
+	Description             *string   `json:"description,omitempty"`
+	IngressFirewallPolicyID *string   `json:"ingress_firewall_policy_id,omitempty"`
+	EgressFirewallPolicyID  *string   `json:"egress_firewall_policy_id,omitempty"`
+	Ports                   *[]string `json:"ports,omitempty"`
+	AdminStateUp            *bool     `json:"admin_state_up,omitempty"`
+	Shared                  *bool     `json:"shared,omitempty"`
+}
+
+func (opts *UpdateFirewallGroupOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.FIREWALLGROUP)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+// FirewallRuleInsertOpts places a rule in a policy, before or after another
+// rule of it, at the top of the policy when both are empty.
+type FirewallRuleInsertOpts struct {
+	FirewallRuleID string `json:"firewall_rule_id" required:"true"`
+	InsertBefore   string `json:"insert_before,omitempty"`
+	InsertAfter    string `json:"insert_after,omitempty"`
+}
+
+func (opts *FirewallRuleInsertOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, "")
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+type FirewallRuleRemoveOpts struct {
+	FirewallRuleID string `json:"firewall_rule_id" required:"true"`
+}
+
+func (opts *FirewallRuleRemoveOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, "")
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"fmt"
+	"go-openstackclient/consts"
+)
+
+type Flavor struct {
+	Id            string            `json:"id"`
+	Name          string            `json:"name"`
+	Description   string            `json:"description"`
+	Ram           int               `json:"ram"`
+	Vcpus         int               `json:"vcpus"`
+	Disk          int               `json:"disk"`
+	// Swap is an empty string for flavors without swap.
+	Swap          interface{}       `json:"swap"`
+	Ephemeral     int               `json:"OS-FLV-EXT-DATA:ephemeral"`
+	IsPublic      bool              `json:"os-flavor-access:is_public"`
+	Disabled      bool              `json:"OS-FLV-DISABLED:disabled"`
+	RxtxFactor    float64           `json:"rxtx_factor"`
+	ExtraSpecs    map[string]string `json:"extra_specs"`
+}
+
+type FlavorMap struct {
+	Flavor `json:"flavor"`
+}
+
+type Flavors struct {
+	Fs            []Flavor          `json:"flavors"`
+	Count         int               `json:"count"`
+}
+
+type CreateFlavorOpts struct {
+	// ID is generated by nova when it is empty.
+	ID            string   `json:"id,omitempty"`
+	Name          string   `json:"name" required:"true"`
+	Description   string   `json:"description,omitempty"`
+	RAM           int      `json:"ram" required:"true"`
+	VCPUs         int      `json:"vcpus" required:"true"`
+	Disk          *int     `json:"disk" required:"true"`
+	Swap          *int     `json:"swap,omitempty"`
+	Ephemeral     *int     `json:"OS-FLV-EXT-DATA:ephemeral,omitempty"`
+	RxTxFactor    float64  `json:"rxtx_factor,omitempty"`
+	IsPublic      *bool    `json:"os-flavor-access:is_public,omitempty"`
+}
+
+func (opts *CreateFlavorOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.FLAVOR)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+// UpdateFlavorOpts changes the description, the only mutable field of a
+// flavor.
+type UpdateFlavorOpts struct {
+	Description   string   `json:"description"`
+}
+
+func (opts *UpdateFlavorOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.FLAVOR)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+type ExtraSpecsOpts struct {
+	ExtraSpecs    map[string]string `json:"extra_specs" required:"true"`
+}
+
+func (opts *ExtraSpecsOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, "")
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+// FlavorAccessOpts grants or revokes the project Tenant the use of a
+// private flavor, Action is addTenantAccess or removeTenantAccess.
+type FlavorAccessOpts struct {
+	Action        string   `json:"-"`
+	Tenant        string   `json:"tenant" required:"true"`
+}
+
+func (opts *FlavorAccessOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, opts.Action)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+type FlavorAccess struct {
+	FlavorId      string   `json:"flavor_id"`
+	TenantId      string   `json:"tenant_id"`
+}
+
+type FlavorAccesses struct {
+	FlavorAccess  []FlavorAccess `json:"flavor_access"`
+}
@@ -0,0 +1,48 @@
+package entity
+
+// Hypervisor is a compute node as nova reports it up to microversion 2.87,
+// the usage fields are gone after it.
+type Hypervisor struct {
+	Id                 string          `json:"id"`
+	HypervisorHostname string          `json:"hypervisor_hostname"`
+	HypervisorType     string          `json:"hypervisor_type"`
+	HostIp             string          `json:"host_ip"`
+	State              string          `json:"state"`
+	Status             string          `json:"status"`
+	Service            struct {
+		Host           string          `json:"host"`
+		Id             string          `json:"id"`
+		DisabledReason interface{}     `json:"disabled_reason"`
+	} `json:"service"`
+	Vcpus              int             `json:"vcpus"`
+	VcpusUsed          int             `json:"vcpus_used"`
+	MemoryMb           int             `json:"memory_mb"`
+	MemoryMbUsed       int             `json:"memory_mb_used"`
+	FreeRamMb          int             `json:"free_ram_mb"`
+	LocalGb            int             `json:"local_gb"`
+	LocalGbUsed        int             `json:"local_gb_used"`
+	FreeDiskGb         int             `json:"free_disk_gb"`
+	RunningVms         int             `json:"running_vms"`
+}
+
+type Hypervisors struct {
+	Hs                 []Hypervisor    `json:"hypervisors"`
+	Count              int             `json:"count"`
+}
+
+type HypervisorStatistics struct {
+	Count              int             `json:"count"`
+	Vcpus              int             `json:"vcpus"`
+	VcpusUsed          int             `json:"vcpus_used"`
+	MemoryMb           int             `json:"memory_mb"`
+	MemoryMbUsed       int             `json:"memory_mb_used"`
+	FreeRamMb          int             `json:"free_ram_mb"`
+	LocalGb            int             `json:"local_gb"`
+	LocalGbUsed        int             `json:"local_gb_used"`
+	FreeDiskGb         int             `json:"free_disk_gb"`
+	RunningVms         int             `json:"running_vms"`
+}
+
+type HypervisorStatisticsMap struct {
+	HypervisorStatistics `json:"hypervisor_statistics"`
+}
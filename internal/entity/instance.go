@@ -182,6 +182,20 @@ type CreateInstanceOpts struct {
 	Tags []string `json:"tags,omitempty"`
 
 	BlockDeviceMappingV2       []BlockDeviceMapping           `json:"block_device_mapping_v2,omitempty"`
+
+	// KeyName is the name of the keypair injected into the server.
+	KeyName string `json:"key_name,omitempty"`
+
+	// FlavorName is resolved to FlavorRef by the controller when FlavorRef
+	// is empty.
+	FlavorName string `json:"-"`
+
+	// ServerGroup is the name or ID of the server group the server joins,
+	// the controller turns it into the group scheduler hint.
+	ServerGroup string `json:"-"`
+
+	// SchedulerHints are sent as os:scheduler_hints, beside the server.
+	SchedulerHints map[string]interface{} `json:"-"`
 }
 
 
@@ -190,38 +204,73 @@ func (opts *CreateInstanceOpts) ToRequestBody() string {
 	if err != nil {
 		panic(fmt.Sprintf("Failed to build request body %s", err))
 	}
-	return reqBody
+	if len(opts.SchedulerHints) == 0 {
+		return reqBody
+	}
+	body := make(map[string]interface{})
+	if err = json.Unmarshal([]byte(reqBody), &body); err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	body["os:scheduler_hints"] = opts.SchedulerHints
+	data, err := json.Marshal(body)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return string(data)
+}
+
+// ComputeService is a nova service, Id is a uuid since microversion 2.53.
+type ComputeService struct {
+	Status         string      `json:"status"`
+	Binary         string      `json:"binary"`
+	Host           string      `json:"host"`
+	Zone           string      `json:"zone"`
+	State          string      `json:"state"`
+	DisabledReason interface{} `json:"disabled_reason"`
+	ForcedDown     bool        `json:"forced_down"`
+	Id             string      `json:"id"`
+	UpdatedAt      string      `json:"updated_at"`
 }
 
 type ComputeServices struct {
-	Services []struct {
-		Status         string      `json:"status"`
-		Binary         string      `json:"binary"`
-		Host           string      `json:"host"`
-		Zone           string      `json:"zone"`
-		State          string      `json:"state"`
-		DisabledReason interface{} `json:"disabled_reason"`
-		Id             int         `json:"id"`
-		UpdatedAt      string      `json:"updated_at"`
-	} `json:"services"`
+	Services []ComputeService `json:"services"`
+}
+
+// UpdateComputeServiceOpts enables or disables a service with Status
+// enabled or disabled, or marks it down while its host is fenced.
+type UpdateComputeServiceOpts struct {
+	Status         string `json:"status,omitempty"`
+	DisabledReason string `json:"disabled_reason,omitempty"`
+	ForcedDown     *bool  `json:"forced_down,omitempty"`
+}
+
+func (opts *UpdateComputeServiceOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, "")
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+type Aggregate struct {
+	AvailabilityZone string            `json:"availability_zone"`
+	CreatedAt        string            `json:"created_at"`
+	Deleted          bool              `json:"deleted"`
+	DeletedAt        interface{}       `json:"deleted_at"`
+	Hosts            []string          `json:"hosts"`
+	Id               int               `json:"id"`
+	Metadata         map[string]string `json:"metadata"`
+	Name             string            `json:"name"`
+	UpdatedAt        string            `json:"updated_at"`
+	Uuid             string            `json:"uuid"`
 }
 
 type AggregateMap struct {
-	Aggregate struct {
-		AvailabilityZone string        `json:"availability_zone"`
-		CreatedAt        string        `json:"created_at"`
-		Deleted          bool          `json:"deleted"`
-		DeletedAt        interface{}   `json:"deleted_at"`
-		Hosts            []interface{} `json:"hosts"`
-		Id               int           `json:"id"`
-		Metadata         struct {
-			AvailabilityZone string `json:"availability_zone"`
-			Key              string `json:"key"`
-		} `json:"metadata"`
-		Name      string `json:"name"`
-		UpdatedAt string `json:"updated_at"`
-		Uuid      string `json:"uuid"`
-	} `json:"aggregate"`
+	Aggregate `json:"aggregate"`
+}
+
+type Aggregates struct {
+	As               []Aggregate       `json:"aggregates"`
 }
 
 type CreateAggregateOpts struct {
@@ -367,13 +416,65 @@ type RemoteConsoleMap struct {
 	} `json:"remote_console"`
 }
 
+// LegacyConsoleOpts requests a console through the os-get<Protocol>Console
+// action of nova before microversion 2.6, Action names the action.
+type LegacyConsoleOpts struct {
+	Action string      `json:"-"`
+	Type   ConsoleType `json:"type" required:"true"`
+}
+
+func (opts *LegacyConsoleOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, opts.Action)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+type LegacyConsoleMap struct {
+	Console struct {
+		Type     string `json:"type"`
+		Url      string `json:"url"`
+	} `json:"console"`
+}
+
+// ConsoleOutputOpts fetches the last Length lines of the console log, all
+// of it when Length is nil.
+type ConsoleOutputOpts struct {
+	Length *int `json:"length,omitempty"`
+}
+
+func (opts *ConsoleOutputOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, "os-getConsoleOutput")
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
 type ImageSnapshot struct {
 	ImageId             string       `json:"image_id"`
 }
 
+// CreateServerImageOpts snapshots a server into the image Name, Metadata
+// becomes image properties.
+type CreateServerImageOpts struct {
+	Name                string            `json:"name" required:"true"`
+	Metadata            map[string]string `json:"metadata,omitempty"`
+}
+
+func (opts *CreateServerImageOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, "createImage")
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
 
-// AssignProps second output parameter is dependent resources slice
-func (opts *CreateInstanceOpts) AssignProps(props map[string]interface{}) (*CreateInstanceOpts, map[string]string) {
+// AssignProps second output parameter is dependent resources slice, "local"
+// images and flavors are the ImageId and FlavorId of conf
+func (opts *CreateInstanceOpts) AssignProps(props map[string]interface{}, conf *configs.Server) (*CreateInstanceOpts, map[string]string) {
 	var deps = make(map[string]string)
 	typ := reflect.TypeOf(opts)
 	val := reflect.ValueOf(opts).Elem()
@@ -387,9 +488,9 @@ func (opts *CreateInstanceOpts) AssignProps(props map[string]interface{}) (*Crea
 			switch fieldType.Kind() {
 			case reflect.String:
 				if compareFieldName == "imageRef" && v.(string) == "local" {
-					value.SetString(configs.CONF.ImageId)
+					value.SetString(conf.ImageId)
 				} else if compareFieldName == "flavorRef" && v.(string) == "local" {
-					value.SetString(configs.CONF.FlavorId)
+					value.SetString(conf.FlavorId)
 				} else {
 					value.SetString(v.(string))
 				}
@@ -430,7 +531,7 @@ func (opts *CreateInstanceOpts) AssignProps(props map[string]interface{}) (*Crea
 						_ = json.Unmarshal(data, &obj)
 						if sourceType, ok := item.(map[string]interface{})["source_type"]; ok {
 							if sourceType.(string) == "image" && obj.Uuid == "local" {
-								obj.Uuid = configs.CONF.ImageId
+								obj.Uuid = conf.ImageId
 							} else {
 								obj.Uuid = sourceType.(string)
 							}
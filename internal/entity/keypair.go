@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"fmt"
+	"go-openstackclient/consts"
+)
+
+type Keypair struct {
+	Name          string `json:"name"`
+	PublicKey     string `json:"public_key"`
+	// PrivateKey is only returned when nova or GenerateKeypair created the
+	// key, it is never stored.
+	PrivateKey    string `json:"private_key,omitempty"`
+	Fingerprint   string `json:"fingerprint"`
+	Type          string `json:"type"`
+	UserId        string `json:"user_id"`
+	CreatedAt     string `json:"created_at"`
+}
+
+type KeypairMap struct {
+	Keypair `json:"keypair"`
+}
+
+// Keypairs is the list of os-keypairs, each entry wrapped in its own
+// keypair object.
+type Keypairs struct {
+	Ks            []KeypairMap `json:"keypairs"`
+}
+
+// CreateKeypairOpts imports PublicKey, nova generates the key pair when it
+// is empty. UserID creates the key of another user, admin only.
+type CreateKeypairOpts struct {
+	Name          string `json:"name" required:"true"`
+	PublicKey     string `json:"public_key,omitempty"`
+	Type          string `json:"type,omitempty"`
+	UserID        string `json:"user_id,omitempty"`
+}
+
+func (opts *CreateKeypairOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.KEYPAIR)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
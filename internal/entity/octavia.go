@@ -670,6 +670,38 @@ func (opts *CreateL7PoliciesOpts) ToRequestBody() string {
 	return reqBody
 }
 
+func (opts *UpdateLoadbalancerOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.LOADBALANCER)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+func (opts *UpdateListenerOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.LISTENER)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+func (opts *UpdatePoolOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.POOL)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+func (opts *UpdateHealthMonitorOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.HEALTHMONITOR)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
 func (opts *CreateRuleOpts) ToRequestBody() string {
 	reqBody, err := BuildRequestBody(opts, consts.L7RULE)
 	if err != nil {
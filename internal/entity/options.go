@@ -1,6 +1,19 @@
 package entity
 
+import "fmt"
+
 
 type CreateUpdateOptions interface {
 	ToRequestBody()     string
 }
+
+// RequestBody builds the body of opts, turning the panic ToRequestBody
+// raises on an invalid body, like a missing required field, into an error.
+func RequestBody(opts CreateUpdateOptions) (body string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return opts.ToRequestBody(), nil
+}
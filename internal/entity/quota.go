@@ -34,4 +34,122 @@ func (opts *NetworkQuotaMap) ToRequestBody() string {
 		panic(fmt.Sprintf("Failed to build request body %s", err))
 	}
 	return reqBody
-}
\ No newline at end of file
+}
+type ComputeQuota struct {
+	Instances                int `json:"instances,omitempty"`
+	Cores                    int `json:"cores,omitempty"`
+	Ram                      int `json:"ram,omitempty"`
+	KeyPairs                 int `json:"key_pairs,omitempty"`
+	MetadataItems            int `json:"metadata_items,omitempty"`
+	ServerGroups             int `json:"server_groups,omitempty"`
+	ServerGroupMembers       int `json:"server_group_members,omitempty"`
+	InjectedFiles            int `json:"injected_files,omitempty"`
+	InjectedFileContentBytes int `json:"injected_file_content_bytes,omitempty"`
+	InjectedFilePathBytes    int `json:"injected_file_path_bytes,omitempty"`
+}
+
+type ComputeQuotaMap struct {
+	ComputeQuota `json:"quota_set"`
+}
+
+
+type VolumeQuota struct {
+	Volumes                  int `json:"volumes,omitempty"`
+	Snapshots                int `json:"snapshots,omitempty"`
+	Gigabytes                int `json:"gigabytes,omitempty"`
+	Backups                  int `json:"backups,omitempty"`
+	BackupGigabytes          int `json:"backup_gigabytes,omitempty"`
+	Groups                   int `json:"groups,omitempty"`
+	PerVolumeGigabytes       int `json:"per_volume_gigabytes,omitempty"`
+}
+
+type VolumeQuotaMap struct {
+	VolumeQuota `json:"quota_set"`
+}
+
+
+// UpdateNetworkQuotaOpts sets the limits that are not nil, 0 included, and
+// keeps the others, -1 is unlimited.
+type UpdateNetworkQuotaOpts struct {
+	Subnet              *int `json:"subnet,omitempty"`
+	Ikepolicy           *int `json:"ikepolicy,omitempty"`
+	Subnetpool          *int `json:"subnetpool,omitempty"`
+	FirewallRule        *int `json:"firewall_rule,omitempty"`
+	Network             *int `json:"network,omitempty"`
+	IpsecSiteConnection *int `json:"ipsec_site_connection,omitempty"`
+	EndpointGroup       *int `json:"endpoint_group,omitempty"`
+	Firewall            *int `json:"firewall,omitempty"`
+	Ipsecpolicy         *int `json:"ipsecpolicy,omitempty"`
+	FirewallPolicy      *int `json:"firewall_policy,omitempty"`
+	SecurityGroupRule   *int `json:"security_group_rule,omitempty"`
+	Vpnservice          *int `json:"vpnservice,omitempty"`
+	Floatingip          *int `json:"floatingip,omitempty"`
+	SecurityGroup       *int `json:"security_group,omitempty"`
+	Router              *int `json:"router,omitempty"`
+	RbacPolicy          *int `json:"rbac_policy,omitempty"`
+	Port                *int `json:"port,omitempty"`
+}
+
+func (opts *UpdateNetworkQuotaOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, "quota")
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+// UpdateComputeQuotaOpts is UpdateNetworkQuotaOpts for nova.
+type UpdateComputeQuotaOpts struct {
+	Instances                *int `json:"instances,omitempty"`
+	Cores                    *int `json:"cores,omitempty"`
+	Ram                      *int `json:"ram,omitempty"`
+	KeyPairs                 *int `json:"key_pairs,omitempty"`
+	MetadataItems            *int `json:"metadata_items,omitempty"`
+	ServerGroups             *int `json:"server_groups,omitempty"`
+	ServerGroupMembers       *int `json:"server_group_members,omitempty"`
+	InjectedFiles            *int `json:"injected_files,omitempty"`
+	InjectedFileContentBytes *int `json:"injected_file_content_bytes,omitempty"`
+	InjectedFilePathBytes    *int `json:"injected_file_path_bytes,omitempty"`
+}
+
+func (opts *UpdateComputeQuotaOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, "quota_set")
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+// UpdateVolumeQuotaOpts is UpdateNetworkQuotaOpts for cinder.
+type UpdateVolumeQuotaOpts struct {
+	Volumes            *int `json:"volumes,omitempty"`
+	Snapshots          *int `json:"snapshots,omitempty"`
+	Gigabytes          *int `json:"gigabytes,omitempty"`
+	Backups            *int `json:"backups,omitempty"`
+	BackupGigabytes    *int `json:"backup_gigabytes,omitempty"`
+	Groups             *int `json:"groups,omitempty"`
+	PerVolumeGigabytes *int `json:"per_volume_gigabytes,omitempty"`
+}
+
+func (opts *UpdateVolumeQuotaOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, "quota_set")
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+// QuotaDetail is the limit of one resource with its usage, -1 is unlimited.
+type QuotaDetail struct {
+	Limit                    int `json:"limit"`
+	InUse                    int `json:"in_use"`
+	Used                     int `json:"used"`
+	Reserved                 int `json:"reserved"`
+}
+
+// Consumed is the amount no longer available to new resources: the used
+// amount, neutron's used or nova's and cinder's in_use, plus the amount
+// reserved by requests still in progress.
+func (d QuotaDetail) Consumed() int {
+	return d.InUse + d.Used + d.Reserved
+}
@@ -1,5 +1,10 @@
 package entity
 
+import (
+	"fmt"
+	"go-openstackclient/consts"
+)
+
 type RbacPolicy struct {
 	TargetTenant string `json:"target_tenant"`
 	TenantId     string `json:"tenant_id"`
@@ -13,3 +18,38 @@ type RbacPolicy struct {
 type RbacPolicyMap struct {
 	RbacPolicy `json:"rbac_policy"`
 }
+
+type RbacPolicies struct {
+	Rps                  []RbacPolicy `json:"rbac_policies"`
+}
+
+// CreateRbacPolicyOpts grants TargetTenant, a project id or "*" for every
+// project, Action on the object. Action is access_as_shared or, for
+// networks, access_as_external.
+type CreateRbacPolicyOpts struct {
+	ObjectType   string `json:"object_type" required:"true"`
+	ObjectID     string `json:"object_id" required:"true"`
+	Action       string `json:"action" required:"true"`
+	TargetTenant string `json:"target_tenant" required:"true"`
+	ProjectID    string `json:"project_id,omitempty"`
+}
+
+func (opts *CreateRbacPolicyOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.RBACPOLICY)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+type UpdateRbacPolicyOpts struct {
+	TargetTenant string `json:"target_tenant" required:"true"`
+}
+
+func (opts *UpdateRbacPolicyOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.RBACPOLICY)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
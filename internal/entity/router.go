@@ -89,7 +89,9 @@ func (opts *CreateRouterOpts) ToRequestBody() string {
 	return reqBody
 }
 
-func (opts *CreateRouterOpts) AssignProps(props map[string]interface{}) (*CreateRouterOpts, map[string]string) {
+// AssignProps second output parameter is dependent resources slice, a
+// "local" gateway network is the ExternalNetwork of conf
+func (opts *CreateRouterOpts) AssignProps(props map[string]interface{}, conf *configs.Server) (*CreateRouterOpts, map[string]string) {
 	var deps = make(map[string]string)
 	typ := reflect.TypeOf(opts)
 	val := reflect.ValueOf(opts).Elem()
@@ -110,7 +112,7 @@ func (opts *CreateRouterOpts) AssignProps(props map[string]interface{}) (*Create
 					obj := GatewayInfo{}
 					if networkId, ok := v.(map[string]interface{})["network_id"]; ok {
 						if networkId.(string) == "local" {
-							obj.NetworkID = configs.CONF.ExternalNetwork
+							obj.NetworkID = conf.ExternalNetwork
 						} else {
 							obj.NetworkID = networkId.(string)
 						}
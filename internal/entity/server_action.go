@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"fmt"
+)
+
+// ServerAction is an action of /servers/{id}/action taking no argument,
+// e.g. os-stop or confirmResize.
+type ServerAction string
+
+func (a ServerAction) ToRequestBody() string {
+	return fmt.Sprintf(`{"%s": null}`, string(a))
+}
+
+const (
+	StartAction          ServerAction = "os-start"
+	StopAction           ServerAction = "os-stop"
+	ConfirmResizeAction  ServerAction = "confirmResize"
+	RevertResizeAction   ServerAction = "revertResize"
+	ShelveAction         ServerAction = "shelve"
+	ShelveOffloadAction  ServerAction = "shelveOffload"
+	UnshelveAction       ServerAction = "unshelve"
+	UnrescueAction       ServerAction = "unrescue"
+)
+
+// RebootOpts reboots with Type SOFT or HARD.
+type RebootOpts struct {
+	Type          string `json:"type" required:"true"`
+}
+
+func (opts *RebootOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, "reboot")
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+type ResizeOpts struct {
+	FlavorRef     string `json:"flavorRef" required:"true"`
+	DiskConfig    string `json:"OS-DCF:diskConfig,omitempty"`
+}
+
+func (opts *ResizeOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, "resize")
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+type RebuildOpts struct {
+	ImageRef      string            `json:"imageRef" required:"true"`
+	Name          string            `json:"name,omitempty"`
+	AdminPass     string            `json:"adminPass,omitempty"`
+	KeyName       string            `json:"key_name,omitempty"`
+	UserData      string            `json:"user_data,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	Description   string            `json:"description,omitempty"`
+}
+
+func (opts *RebuildOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, "rebuild")
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+type RescueOpts struct {
+	AdminPass       string `json:"adminPass,omitempty"`
+	RescueImageRef  string `json:"rescue_image_ref,omitempty"`
+}
+
+func (opts *RescueOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, "rescue")
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+// MigrateOpts cold migrates to Host, the scheduler picks one when it is
+// empty.
+type MigrateOpts struct {
+	Host          string `json:"host,omitempty"`
+}
+
+func (opts *MigrateOpts) ToRequestBody() string {
+	if opts.Host == "" {
+		return `{"migrate": null}`
+	}
+	reqBody, err := BuildRequestBody(opts, "migrate")
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+// LiveMigrateOpts live migrates to Host, or where the scheduler decides when
+// it is empty. BlockMigration is true, false or "auto", the default.
+type LiveMigrateOpts struct {
+	Host            *string     `json:"host"`
+	BlockMigration  interface{} `json:"block_migration"`
+}
+
+func (opts *LiveMigrateOpts) ToRequestBody() string {
+	if opts.BlockMigration == nil {
+		opts.BlockMigration = "auto"
+	}
+	reqBody, err := BuildRequestBody(opts, "os-migrateLive")
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
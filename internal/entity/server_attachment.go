@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"fmt"
+	"go-openstackclient/consts"
+)
+
+// VolumeAttachment is a volume attached to a server as nova sees it, Id is
+// the volume id.
+type VolumeAttachment struct {
+	Id            string   `json:"id"`
+	VolumeId      string   `json:"volumeId"`
+	ServerId      string   `json:"serverId"`
+	Device        string   `json:"device"`
+	Tag           string   `json:"tag"`
+}
+
+type VolumeAttachmentMap struct {
+	VolumeAttachment `json:"volumeAttachment"`
+}
+
+// CreateVolumeAttachmentOpts attaches VolumeId, nova picks the Device when
+// it is empty and most hypervisors ignore it anyway.
+type CreateVolumeAttachmentOpts struct {
+	VolumeId      string   `json:"volumeId" required:"true"`
+	Device        string   `json:"device,omitempty"`
+	Tag           string   `json:"tag,omitempty"`
+}
+
+func (opts *CreateVolumeAttachmentOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.VOLUMEATTACHMENT)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+type InterfaceFixedIp struct {
+	SubnetId      string   `json:"subnet_id,omitempty"`
+	IpAddress     string   `json:"ip_address,omitempty"`
+}
+
+type InterfaceAttachment struct {
+	PortId        string             `json:"port_id"`
+	NetId         string             `json:"net_id"`
+	MacAddr       string             `json:"mac_addr"`
+	PortState     string             `json:"port_state"`
+	FixedIps      []InterfaceFixedIp `json:"fixed_ips"`
+	Tag           string             `json:"tag"`
+}
+
+type InterfaceAttachmentMap struct {
+	InterfaceAttachment `json:"interfaceAttachment"`
+}
+
+// CreateInterfaceAttachmentOpts attaches the existing port PortId, or a new
+// port of NetId that nova deletes again on detach.
+type CreateInterfaceAttachmentOpts struct {
+	PortId        string             `json:"port_id,omitempty"`
+	NetId         string             `json:"net_id,omitempty"`
+	FixedIps      []InterfaceFixedIp `json:"fixed_ips,omitempty"`
+	Tag           string             `json:"tag,omitempty"`
+}
+
+func (opts *CreateInterfaceAttachmentOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.INTERFACEATTACHMENT)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"fmt"
+	"go-openstackclient/consts"
+)
+
+type ServerGroupRules struct {
+	// MaxServerPerHost limits the anti-affinity policy.
+	MaxServerPerHost int `json:"max_server_per_host,omitempty"`
+}
+
+// ServerGroup is a server group as returned since microversion 2.64, with a
+// single policy.
+type ServerGroup struct {
+	Id            string            `json:"id"`
+	Name          string            `json:"name"`
+	Policy        string            `json:"policy"`
+	Rules         ServerGroupRules  `json:"rules"`
+	Members       []string          `json:"members"`
+	ProjectId     string            `json:"project_id"`
+	UserId        string            `json:"user_id"`
+}
+
+type ServerGroupMap struct {
+	ServerGroup `json:"server_group"`
+}
+
+// CreateServerGroupOpts creates a group with Policy affinity,
+// anti-affinity, soft-affinity or soft-anti-affinity.
+type CreateServerGroupOpts struct {
+	Name          string            `json:"name" required:"true"`
+	Policy        string            `json:"policy" required:"true"`
+	Rules         *ServerGroupRules `json:"rules,omitempty"`
+}
+
+func (opts *CreateServerGroupOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.SERVERGROUP)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
@@ -65,3 +65,33 @@ func (opts *Dnat) ToRequestBody() string {
 type Dnats struct {
     Ds           []Dnat         `json:"dnats"`
 }
+
+type UpdateSnatOpts struct {
+    Name          *string  `json:"name,omitempty"`
+    SnatIpAddress *string  `json:"snat_ip_address,omitempty"`
+    OriginalCidrs []string `json:"original_cidrs,omitempty"`
+}
+
+func (opts *UpdateSnatOpts) ToRequestBody() string {
+    reqBody, err := BuildRequestBody(opts, consts.Snat)
+    if err != nil {
+        panic(fmt.Sprintf("Failed to build request body %s", err))
+    }
+    return reqBody
+}
+
+type UpdateDnatOpts struct {
+    Name           *string `json:"name,omitempty"`
+    FixedIpAddress string  `json:"fixed_ip_address,omitempty"`
+    FixedIpPort    int     `json:"fixed_ip_port,omitempty"`
+    FloatingIpPort int     `json:"floating_ip_port,omitempty"`
+    Protocol       string  `json:"protocol,omitempty"`
+}
+
+func (opts *UpdateDnatOpts) ToRequestBody() string {
+    reqBody, err := BuildRequestBody(opts, consts.Dnat)
+    if err != nil {
+        panic(fmt.Sprintf("Failed to build request body %s", err))
+    }
+    return reqBody
+}
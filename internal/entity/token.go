@@ -2,11 +2,14 @@ package entity
 
 import (
 	"fmt"
+	"time"
 )
 
 type Identity struct {
-	Methods  []string `json:"methods"`
-	Password          `json:"password"`
+	Methods               []string               `json:"methods"`
+	Password              *Password              `json:"password,omitempty"`
+	Token                 *TokenId               `json:"token,omitempty"`
+	ApplicationCredential *ApplicationCredential `json:"application_credential,omitempty"`
 }
 
 type Password struct {
@@ -14,27 +17,50 @@ type Password struct {
 }
 
 type Domain struct {
-	Name string `json:"name"`
+	Id   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
 }
 
 type Userr struct {
-	Domain           `json:"domain"`
-	Name     string  `json:"name"`
-	Password string  `json:"password"`
+	Id       string  `json:"id,omitempty"`
+	Name     string  `json:"name,omitempty"`
+	Password string  `json:"password,omitempty"`
+	Domain   *Domain `json:"domain,omitempty"`
+}
+
+type TokenId struct {
+	Id string `json:"id"`
+}
+
+// ApplicationCredential is looked up by id, or by name together with the
+// owning user.
+type ApplicationCredential struct {
+	Id     string `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Secret string `json:"secret"`
+	User   *Userr `json:"user,omitempty"`
 }
 
 type Auth struct {
      Identity           `json:"identity"`
-     Scope              `json:"scope"`
+     Scope     *Scope   `json:"scope,omitempty"`
 }
 
 type Projectt struct {
-	Name string       `json:"name"`
-	Domain            `json:"domain"`
+	Id     string  `json:"id,omitempty"`
+	Name   string  `json:"name,omitempty"`
+	Domain *Domain `json:"domain,omitempty"`
 }
 
+type System struct {
+	All bool `json:"all"`
+}
+
+// Scope holds exactly one of a project, domain or system scope.
 type Scope struct {
-	Projectt           `json:"project"`
+	Projectt  *Projectt  `json:"project,omitempty"`
+	Domain    *Domain    `json:"domain,omitempty"`
+	System    *System    `json:"system,omitempty"`
 }
 
 type AuthOption struct {
@@ -49,3 +75,39 @@ func (opts *AuthOption) ToRequestBody() string {
 	}
 	return reqBody
 }
+
+type Endpoint struct {
+	Id        string `json:"id"`
+	Interface string `json:"interface"`
+	Region    string `json:"region"`
+	RegionId  string `json:"region_id"`
+	Url       string `json:"url"`
+}
+
+type CatalogEntry struct {
+	Id        string     `json:"id"`
+	Name      string     `json:"name"`
+	Type      string     `json:"type"`
+	Endpoints []Endpoint `json:"endpoints"`
+}
+
+type TokenProject struct {
+	Id     string `json:"id"`
+	Name   string `json:"name"`
+	Domain struct {
+		Id   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"domain"`
+}
+
+type Token struct {
+	ExpiresAt time.Time      `json:"expires_at"`
+	IssuedAt  time.Time      `json:"issued_at"`
+	Methods   []string       `json:"methods"`
+	Project   TokenProject   `json:"project"`
+	Catalog   []CatalogEntry `json:"catalog"`
+}
+
+type TokenMap struct {
+	Token `json:"token"`
+}
@@ -1,5 +1,10 @@
 package entity
 
+import (
+	"fmt"
+	"go-openstackclient/consts"
+)
+
 
 type Attachment struct {
 	ServerId     string      `json:"server_id"`
@@ -70,3 +75,22 @@ type VolumeToImage struct {
 		VolumeType         interface{} `json:"volume_type"`
 	} `json:"os-volume_upload_image"`
 }
+
+// UploadVolumeToImageOpts uploads a volume to the image ImageName, Force
+// allows uploading an in-use volume.
+type UploadVolumeToImageOpts struct {
+	ImageName          string      `json:"image_name" required:"true"`
+	Force              bool        `json:"force,omitempty"`
+	DiskFormat         string      `json:"disk_format,omitempty"`
+	ContainerFormat    string      `json:"container_format,omitempty"`
+	Visibility         string      `json:"visibility,omitempty"`
+	Protected          *bool       `json:"protected,omitempty"`
+}
+
+func (opts *UploadVolumeToImageOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.OSVOLUMEUPLOADIMAGE)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
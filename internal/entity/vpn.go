@@ -1,5 +1,9 @@
 package entity
 
+import (
+	"fmt"
+	"go-openstackclient/consts"
+)
 
 type VpnService struct {
 	RouterId     string      `json:"router_id"`
@@ -132,3 +136,208 @@ type IpsecConnections struct {
 	ICs          []IpsecSiteConnection `json:"ipsec_site_connections"`
 	Count              int             `json:"count"`
 }
+
+// Lifetime is the security association lifetime of IKE and IPsec policies.
+type Lifetime struct {
+	Units string `json:"units,omitempty"`
+	Value int    `json:"value,omitempty"`
+}
+
+// DPD is the dead peer detection protocol of a site connection.
+type DPD struct {
+	Action   string `json:"action,omitempty"`
+	Interval int    `json:"interval,omitempty"`
+	Timeout  int    `json:"timeout,omitempty"`
+}
+
+type CreateVpnServiceOpts struct {
+	RouterID     string `json:"router_id" required:"true"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+	// SubnetID is only set for the legacy mode without endpoint groups.
+	SubnetID     string `json:"subnet_id,omitempty"`
+	AdminStateUp *bool  `json:"admin_state_up,omitempty"`
+	FlavorID     string `json:"flavor_id,omitempty"`
+	ProjectID    string `json:"project_id,omitempty"`
+}
+
+func (opts *CreateVpnServiceOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.VPNSERVICE)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+type UpdateVpnServiceOpts struct {
+	Name         *string `json:"name,omitempty"`
+	Description  *string `json:"description,omitempty"`
+	AdminStateUp *bool   `json:"admin_state_up,omitempty"`
+}
+
+func (opts *UpdateVpnServiceOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.VPNSERVICE)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+type CreateEndpointGroupOpts struct {
+	// Type is subnet for local endpoints and cidr for peer endpoints.
+	Type        string   `json:"type" required:"true"`
+	Endpoints   []string `json:"endpoints" required:"true"`
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	ProjectID   string   `json:"project_id,omitempty"`
+}
+
+func (opts *CreateEndpointGroupOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.ENDPOINTGROUP)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+type UpdateEndpointGroupOpts struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+func (opts *UpdateEndpointGroupOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.ENDPOINTGROUP)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+type CreateIkePolicyOpts struct {
+	Name                  string    `json:"name,omitempty"`
+	Description           string    `json:"description,omitempty"`
+	AuthAlgorithm         string    `json:"auth_algorithm,omitempty"`
+	EncryptionAlgorithm   string    `json:"encryption_algorithm,omitempty"`
+	PFS                   string    `json:"pfs,omitempty"`
+	Phase1NegotiationMode string    `json:"phase1_negotiation_mode,omitempty"`
+	IKEVersion            string    `json:"ike_version,omitempty"`
+	Lifetime              *Lifetime `json:"lifetime,omitempty"`
+	ProjectID             string    `json:"project_id,omitempty"`
+}
+
+func (opts *CreateIkePolicyOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.IKEPOLICY)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+type UpdateIkePolicyOpts struct {
+	Name                  *string   `json:"name,omitempty"`
+	Description           *string   `json:"description,omitempty"`
+	AuthAlgorithm         string    `json:"auth_algorithm,omitempty"`
+	EncryptionAlgorithm   string    `json:"encryption_algorithm,omitempty"`
+	PFS                   string    `json:"pfs,omitempty"`
+	Phase1NegotiationMode string    `json:"phase1_negotiation_mode,omitempty"`
+	IKEVersion            string    `json:"ike_version,omitempty"`
+	Lifetime              *Lifetime `json:"lifetime,omitempty"`
+}
+
+func (opts *UpdateIkePolicyOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.IKEPOLICY)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+type CreateIpsecPolicyOpts struct {
+	Name                string    `json:"name,omitempty"`
+	Description         string    `json:"description,omitempty"`
+	AuthAlgorithm       string    `json:"auth_algorithm,omitempty"`
+	EncapsulationMode   string    `json:"encapsulation_mode,omitempty"`
+	EncryptionAlgorithm string    `json:"encryption_algorithm,omitempty"`
+	PFS                 string    `json:"pfs,omitempty"`
+	TransformProtocol   string    `json:"transform_protocol,omitempty"`
+	Lifetime            *Lifetime `json:"lifetime,omitempty"`
+	ProjectID           string    `json:"project_id,omitempty"`
+}
+
+func (opts *CreateIpsecPolicyOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.IPSECPOLICY)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+type UpdateIpsecPolicyOpts struct {
+	Name                *string   `json:"name,omitempty"`
+	Description         *string   `json:"description,omitempty"`
+	AuthAlgorithm       string    `json:"auth_algorithm,omitempty"`
+	EncapsulationMode   string    `json:"encapsulation_mode,omitempty"`
+	EncryptionAlgorithm string    `json:"encryption_algorithm,omitempty"`
+	PFS                 string    `json:"pfs,omitempty"`
+	TransformProtocol   string    `json:"transform_protocol,omitempty"`
+	Lifetime            *Lifetime `json:"lifetime,omitempty"`
+}
+
+func (opts *UpdateIpsecPolicyOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.IPSECPOLICY)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+type CreateIpsecSiteConnectionOpts struct {
+	VpnserviceID   string   `json:"vpnservice_id" required:"true"`
+	IkepolicyID    string   `json:"ikepolicy_id" required:"true"`
+	IpsecpolicyID  string   `json:"ipsecpolicy_id" required:"true"`
+	PeerAddress    string   `json:"peer_address" required:"true"`
+	PeerID         string   `json:"peer_id" required:"true"`
+	PSK            string   `json:"psk" required:"true"`
+	LocalEPGroupID string   `json:"local_ep_group_id,omitempty"`
+	PeerEPGroupID  string   `json:"peer_ep_group_id,omitempty"`
+	// PeerCIDRs is only set for the legacy mode without endpoint groups.
+	PeerCIDRs      []string `json:"peer_cidrs,omitempty"`
+	Name           string   `json:"name,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	LocalID        string   `json:"local_id,omitempty"`
+	MTU            int      `json:"mtu,omitempty"`
+	Initiator      string   `json:"initiator,omitempty"`
+	AdminStateUp   *bool    `json:"admin_state_up,omitempty"`
+	DPD            *DPD     `json:"dpd,omitempty"`
+	ProjectID      string   `json:"project_id,omitempty"`
+}
+
+func (opts *CreateIpsecSiteConnectionOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.IPSECCONNECTION)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
+
+type UpdateIpsecSiteConnectionOpts struct {
+	Name           *string  `json:"name,omitempty"`
+	Description    *string  `json:"description,omitempty"`
+	PeerAddress    string   `json:"peer_address,omitempty"`
+	PeerID         string   `json:"peer_id,omitempty"`
+	PSK            string   `json:"psk,omitempty"`
+	LocalEPGroupID string   `json:"local_ep_group_id,omitempty"`
+	PeerEPGroupID  string   `json:"peer_ep_group_id,omitempty"`
+	MTU            int      `json:"mtu,omitempty"`
+	Initiator      string   `json:"initiator,omitempty"`
+	AdminStateUp   *bool    `json:"admin_state_up,omitempty"`
+	DPD            *DPD     `json:"dpd,omitempty"`
+}
+
+func (opts *UpdateIpsecSiteConnectionOpts) ToRequestBody() string {
+	reqBody, err := BuildRequestBody(opts, consts.IPSECCONNECTION)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to build request body %s", err))
+	}
+	return reqBody
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-openstackclient/consts"
+	"go-openstackclient/internal/entity"
+	"log"
+	"sort"
+	"strconv"
+)
+
+type AggregateClient = ResourceClient[entity.Aggregate, *entity.CreateAggregateOpts, *entity.UpdateAggregateOpts]
+
+// Aggregates is admin only, nova returns every aggregate in one page.
+func (s *Controller) Aggregates() *AggregateClient {
+	return NewResourceClient[entity.Aggregate, *entity.CreateAggregateOpts, *entity.UpdateAggregateOpts](s, ResourceSpec{
+		Resource: consts.AGGREGATE, Collection: consts.OSAGGREGATES,
+		Key: consts.AGGREGATE, PluralKey: consts.AGGREGATES, Paging: MarkerPaging})
+}
+
+func (s *Controller) CreateAggregate(ctx context.Context, opts *entity.CreateAggregateOpts) (entity.Aggregate, error) {
+	return s.Aggregates().Create(ctx, opts)
+}
+
+func (s *Controller) GetAggregate(ctx context.Context, aggregateId int) (entity.Aggregate, error) {
+	return s.Aggregates().Get(ctx, strconv.Itoa(aggregateId))
+}
+
+func (s *Controller) ListAggregates(ctx context.Context) ([]entity.Aggregate, error) {
+	return s.Aggregates().List(ctx, "")
+}
+
+func (s *Controller) UpdateAggregate(ctx context.Context, aggregateId int, opts *entity.UpdateAggregateOpts) (entity.Aggregate, error) {
+	return s.Aggregates().Update(ctx, strconv.Itoa(aggregateId), opts)
+}
+
+// DeleteAggregate deletes an aggregate, nova refuses while it still has
+// hosts.
+func (s *Controller) DeleteAggregate(ctx context.Context, aggregateId int) error {
+	return s.Aggregates().Delete(ctx, strconv.Itoa(aggregateId))
+}
+
+// FindAggregate returns the aggregate named name, nova can't filter the list
+// by name.
+func (s *Controller) FindAggregate(ctx context.Context, name string) (entity.Aggregate, error) {
+	aggregates, err := s.ListAggregates(ctx)
+	if err != nil {
+		return entity.Aggregate{}, err
+	}
+	for _, aggregate := range aggregates {
+		if aggregate.Name == name {
+			return aggregate, nil
+		}
+	}
+	return entity.Aggregate{}, fmt.Errorf("%s %q: %w", consts.AGGREGATE, name, ErrNotFound)
+}
+
+func (s *Controller) aggregateAction(ctx context.Context, aggregateId int, body entity.CreateUpdateOptions) (entity.Aggregate, error) {
+	var aggregate entity.AggregateMap
+	err := s.Aggregates().call(ctx, CREATE, strconv.Itoa(aggregateId), "action", body, &aggregate)
+	return aggregate.Aggregate, err
+}
+
+func (s *Controller) AddAggregateHost(ctx context.Context, aggregateId int, host string) (entity.Aggregate, error) {
+	aggregate, err := s.aggregateAction(ctx, aggregateId, &entity.AddHostOpts{Host: host})
+	if err == nil {
+		log.Println("==============Add aggregate host success", aggregateId, host)
+	}
+	return aggregate, err
+}
+
+func (s *Controller) RemoveAggregateHost(ctx context.Context, aggregateId int, host string) (entity.Aggregate, error) {
+	aggregate, err := s.aggregateAction(ctx, aggregateId, &entity.RemoveHostOpts{Host: host})
+	if err == nil {
+		log.Println("==============Remove aggregate host success", aggregateId, host)
+	}
+	return aggregate, err
+}
+
+// SetAggregateMetadata merges metadata into the aggregate's, a nil value
+// removes the key.
+func (s *Controller) SetAggregateMetadata(ctx context.Context, aggregateId int, metadata map[string]interface{}) (entity.Aggregate, error) {
+	aggregate, err := s.aggregateAction(ctx, aggregateId, &entity.SetMetadataOpts{Metadata: metadata})
+	if err == nil {
+		log.Println("==============Set aggregate metadata success", aggregateId)
+	}
+	return aggregate, err
+}
+
+// AggregateDiff is what EnsureAggregate changed to converge an aggregate.
+type AggregateDiff struct {
+	Name                string
+	Created             bool
+	// OldAvailabilityZone is set when the zone moved to the desired one.
+	OldAvailabilityZone string
+	AvailabilityZone    string
+	AddedHosts          []string
+	RemovedHosts        []string
+	SetMetadata         map[string]string
+	UnsetMetadata       []string
+}
+
+func (d AggregateDiff) Changed() bool {
+	return d.Created || d.OldAvailabilityZone != "" || len(d.AddedHosts) != 0 ||
+		len(d.RemovedHosts) != 0 || len(d.SetMetadata) != 0 || len(d.UnsetMetadata) != 0
+}
+
+// EnsureAggregate converges the aggregate named name to the availability
+// zone, hosts and metadata given, creating it when missing, and reports
+// what it changed. The availability_zone metadata key follows az and is
+// left out of the metadata comparison.
+func (s *Controller) EnsureAggregate(ctx context.Context, name, az string, hosts []string, metadata map[string]string) (entity.Aggregate, AggregateDiff, error) {
+	diff := AggregateDiff{Name: name, AvailabilityZone: az,
+		SetMetadata: make(map[string]string), UnsetMetadata: make([]string, 0)}
+	aggregate, err := s.FindAggregate(ctx, name)
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrNotFound):
+		if aggregate, err = s.CreateAggregate(ctx, &entity.CreateAggregateOpts{
+			Name: name, AvailabilityZone: az}); err != nil {
+			return aggregate, diff, err
+		}
+		diff.Created = true
+	default:
+		return aggregate, diff, err
+	}
+
+	if !diff.Created && aggregate.AvailabilityZone != az {
+		diff.OldAvailabilityZone = aggregate.AvailabilityZone
+		if diff.OldAvailabilityZone == "" {
+			diff.OldAvailabilityZone = "(none)"
+		}
+		if az == "" {
+			// an empty zone in the update body is dropped, unset the key instead
+			if aggregate, err = s.SetAggregateMetadata(ctx, aggregate.Id,
+				map[string]interface{}{"availability_zone": nil}); err != nil {
+				return aggregate, diff, err
+			}
+		} else if aggregate, err = s.UpdateAggregate(ctx, aggregate.Id,
+			&entity.UpdateAggregateOpts{AvailabilityZone: az}); err != nil {
+			return aggregate, diff, err
+		}
+	}
+
+	current := make(map[string]struct{}, len(aggregate.Hosts))
+	for _, host := range aggregate.Hosts {
+		current[host] = struct{}{}
+	}
+	desired := make(map[string]struct{}, len(hosts))
+	for _, host := range hosts {
+		desired[host] = struct{}{}
+		if _, ok := current[host]; !ok {
+			if aggregate, err = s.AddAggregateHost(ctx, aggregate.Id, host); err != nil {
+				return aggregate, diff, err
+			}
+			diff.AddedHosts = append(diff.AddedHosts, host)
+		}
+	}
+	for host := range current {
+		if _, ok := desired[host]; !ok {
+			if aggregate, err = s.RemoveAggregateHost(ctx, aggregate.Id, host); err != nil {
+				return aggregate, diff, err
+			}
+			diff.RemovedHosts = append(diff.RemovedHosts, host)
+		}
+	}
+	sort.Strings(diff.RemovedHosts)
+
+	changes := make(map[string]interface{})
+	for key, value := range metadata {
+		if key == "availability_zone" {
+			continue
+		}
+		if old, ok := aggregate.Metadata[key]; !ok || old != value {
+			changes[key] = value
+			diff.SetMetadata[key] = value
+		}
+	}
+	for key := range aggregate.Metadata {
+		if _, ok := metadata[key]; !ok && key != "availability_zone" {
+			changes[key] = nil
+			diff.UnsetMetadata = append(diff.UnsetMetadata, key)
+		}
+	}
+	sort.Strings(diff.UnsetMetadata)
+	if len(changes) != 0 {
+		if aggregate, err = s.SetAggregateMetadata(ctx, aggregate.Id, changes); err != nil {
+			return aggregate, diff, err
+		}
+	}
+	log.Printf("==============Ensure aggregate %s success, changed %v: %+v", name, diff.Changed(), diff)
+	return aggregate, diff, nil
+}
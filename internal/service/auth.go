@@ -0,0 +1,185 @@
+package service
+
+import (
+	"fmt"
+	"go-openstackclient/configs"
+	"go-openstackclient/internal/entity"
+)
+
+const (
+	PasswordAuthType              = "password"
+	TokenAuthType                 = "token"
+	ApplicationCredentialAuthType = "v3applicationcredential"
+
+	defaultDomain = "default"
+)
+
+// AuthPlugin builds the body of the keystone token request for one way of
+// authenticating.
+type AuthPlugin interface {
+	AuthOptions() *entity.AuthOption
+}
+
+// AuthScope selects what the token is scoped to. At most one of a project, a
+// domain or the system is used, in that order; an empty scope leaves it to
+// keystone's default project for the user.
+type AuthScope struct {
+	ProjectId         string
+	ProjectName       string
+	ProjectDomainName string
+	DomainId          string
+	DomainName        string
+	System            bool
+}
+
+func (a AuthScope) scope() *entity.Scope {
+	switch {
+	case a.ProjectId != "":
+		return &entity.Scope{Projectt: &entity.Projectt{Id: a.ProjectId}}
+	case a.ProjectName != "":
+		return &entity.Scope{Projectt: &entity.Projectt{
+			Name: a.ProjectName, Domain: &entity.Domain{Name: orDefaultDomain(a.ProjectDomainName)}}}
+	case a.DomainId != "":
+		return &entity.Scope{Domain: &entity.Domain{Id: a.DomainId}}
+	case a.DomainName != "":
+		return &entity.Scope{Domain: &entity.Domain{Name: a.DomainName}}
+	case a.System:
+		return &entity.Scope{System: &entity.System{All: true}}
+	}
+	return nil
+}
+
+// PasswordAuth authenticates the user UserId, or UserName of
+// UserDomainName when it is empty.
+type PasswordAuth struct {
+	UserId         string
+	UserName       string
+	UserDomainName string
+	Password       string
+	Scope          AuthScope
+}
+
+func (p *PasswordAuth) AuthOptions() *entity.AuthOption {
+	return &entity.AuthOption{
+		Auth: entity.Auth{
+			Identity: entity.Identity{
+				Methods: []string{PasswordAuthType},
+				Password: &entity.Password{
+					Userr: userOf(p.UserId, p.UserName, p.UserDomainName, p.Password),
+				},
+			},
+			Scope: p.Scope.scope(),
+		},
+	}
+}
+
+// TokenAuth exchanges an existing token, typically to rescope it.
+type TokenAuth struct {
+	Token string
+	Scope AuthScope
+}
+
+func (t *TokenAuth) AuthOptions() *entity.AuthOption {
+	return &entity.AuthOption{
+		Auth: entity.Auth{
+			Identity: entity.Identity{
+				Methods: []string{TokenAuthType},
+				Token: &entity.TokenId{Id: t.Token},
+			},
+			Scope: t.Scope.scope(),
+		},
+	}
+}
+
+// ApplicationCredentialAuth authenticates with an application credential,
+// which is always scoped to the project it was created in.
+type ApplicationCredentialAuth struct {
+	Id             string
+	Name           string
+	Secret         string
+	UserId         string
+	UserName       string
+	UserDomainName string
+}
+
+func (a *ApplicationCredentialAuth) AuthOptions() *entity.AuthOption {
+	credential := &entity.ApplicationCredential{Id: a.Id, Secret: a.Secret}
+	if a.Id == "" {
+		credential.Name = a.Name
+		user := userOf(a.UserId, a.UserName, a.UserDomainName, "")
+		credential.User = &user
+	}
+	return &entity.AuthOption{
+		Auth: entity.Auth{
+			Identity: entity.Identity{
+				Methods: []string{"application_credential"},
+				ApplicationCredential: credential,
+			},
+		},
+	}
+}
+
+// NewAuthPlugin builds the auth plugin selected by conf.AuthType.
+func NewAuthPlugin(conf configs.Openstack) (AuthPlugin, error) {
+	scope := AuthScope{
+		ProjectId: conf.ProjectId,
+		ProjectName: conf.ProjectName,
+		ProjectDomainName: conf.ProjectDomainName,
+		DomainName: conf.DomainName,
+		System: conf.SystemScope == "all",
+	}
+	if conf.DomainName != "" || scope.System {
+		scope.ProjectId, scope.ProjectName = "", ""
+	}
+
+	switch conf.AuthType {
+	case "", PasswordAuthType:
+		if (conf.UserName == "" && conf.UserId == "") || conf.UserPassword == "" {
+			return nil, fmt.Errorf("password auth requires UserName or UserId, and UserPassword")
+		}
+		return &PasswordAuth{
+			UserId: conf.UserId,
+			UserName: conf.UserName,
+			UserDomainName: conf.UserDomainName,
+			Password: conf.UserPassword,
+			Scope: scope,
+		}, nil
+	case TokenAuthType:
+		if conf.Token == "" {
+			return nil, fmt.Errorf("token auth requires Token")
+		}
+		return &TokenAuth{Token: conf.Token, Scope: scope}, nil
+	case ApplicationCredentialAuthType:
+		if conf.ApplicationCredentialSecret == "" {
+			return nil, fmt.Errorf("application credential auth requires ApplicationCredentialSecret")
+		}
+		if conf.ApplicationCredentialId == "" && (conf.ApplicationCredentialName == "" || (conf.UserName == "" && conf.UserId == "")) {
+			return nil, fmt.Errorf("application credential auth requires ApplicationCredentialId, " +
+				"or ApplicationCredentialName with UserName or UserId")
+		}
+		return &ApplicationCredentialAuth{
+			Id: conf.ApplicationCredentialId,
+			Name: conf.ApplicationCredentialName,
+			Secret: conf.ApplicationCredentialSecret,
+			UserId: conf.UserId,
+			UserName: conf.UserName,
+			UserDomainName: conf.UserDomainName,
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported auth type %q", conf.AuthType)
+}
+
+// userOf identifies a user by id, which needs no domain, or by name.
+func userOf(id, name, domain, password string) entity.Userr {
+	if id != "" {
+		return entity.Userr{Id: id, Password: password}
+	}
+	return entity.Userr{Name: name, Password: password, Domain: &entity.Domain{Name: orDefaultDomain(domain)}}
+}
+
+func orDefaultDomain(domain string) string {
+	if domain == "" {
+		return defaultDomain
+	}
+	return domain
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"encoding/json"
+	"go-openstackclient/configs"
+	"testing"
+)
+
+func TestNewAuthPlugin(t *testing.T) {
+	tests := []struct {
+		name  string
+		conf  configs.Openstack
+		want  string
+	}{
+		{
+			name: "user and project names",
+			conf: configs.Openstack{UserName: "u", UserPassword: "p", ProjectName: "demo"},
+			want: `{"auth":{"identity":{"methods":["password"],"password":{"user":{"name":"u","password":"p","domain":{"name":"default"}}}},` +
+				`"scope":{"project":{"name":"demo","domain":{"name":"default"}}}}}`,
+		},
+		{
+			name: "user and project ids",
+			conf: configs.Openstack{UserId: "u1", UserName: "u", UserPassword: "p", ProjectId: "p1", ProjectName: "demo"},
+			want: `{"auth":{"identity":{"methods":["password"],"password":{"user":{"id":"u1","password":"p"}}},` +
+				`"scope":{"project":{"id":"p1"}}}}`,
+		},
+		{
+			name: "system scope over project id",
+			conf: configs.Openstack{UserId: "u1", UserPassword: "p", ProjectId: "p1", SystemScope: "all"},
+			want: `{"auth":{"identity":{"methods":["password"],"password":{"user":{"id":"u1","password":"p"}}},` +
+				`"scope":{"system":{"all":true}}}}`,
+		},
+		{
+			name: "application credential of a user id",
+			conf: configs.Openstack{AuthType: ApplicationCredentialAuthType, UserId: "u1",
+				ApplicationCredentialName: "ac", ApplicationCredentialSecret: "s"},
+			want: `{"auth":{"identity":{"methods":["application_credential"],` +
+				`"application_credential":{"name":"ac","secret":"s","user":{"id":"u1"}}}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin, err := NewAuthPlugin(tt.conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err := json.Marshal(plugin.AuthOptions())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(body) != tt.want {
+				t.Errorf("AuthOptions() = %s\nwant %s", body, tt.want)
+			}
+		})
+	}
+}
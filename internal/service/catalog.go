@@ -0,0 +1,80 @@
+package service
+
+import (
+	"go-openstackclient/configs"
+	"go-openstackclient/consts"
+	"go-openstackclient/internal/entity"
+	"strings"
+	"sync"
+)
+
+// Catalog holds the service catalog returned with the keystone token and
+// resolves endpoints by service type, interface and region.
+type Catalog struct {
+	mu          sync.RWMutex
+	entries     []entity.CatalogEntry
+	projectID   string
+	iface       string
+	region      string
+}
+
+// set stores the catalog of a new token along with the interface and region
+// of the profile it was issued for.
+func (c *Catalog) set(entries []entity.CatalogEntry, projectID string, conf *configs.Openstack) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = entries
+	c.projectID = projectID
+	c.iface = conf.Interface
+	c.region = conf.Region
+}
+
+// URL returns the endpoint of the first of serviceTypes found in the catalog
+// for the configured interface and region, normalised to end with version.
+func (c *Catalog) URL(version string, serviceTypes ...string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	iface := c.iface
+	if iface == "" {
+		iface = consts.PublicInterface
+	}
+	region := c.region
+	for _, serviceType := range serviceTypes {
+		for _, entry := range c.entries {
+			if entry.Type != serviceType {
+				continue
+			}
+			for _, endpoint := range entry.Endpoints {
+				if endpoint.Interface != iface {
+					continue
+				}
+				if region != "" && endpoint.Region != region && endpoint.RegionId != region {
+					continue
+				}
+				return normalizeEndpoint(endpoint.Url, version, c.projectID), true
+			}
+		}
+	}
+	return "", false
+}
+
+// normalizeEndpoint strips the trailing slash and project id some services
+// register in their catalog url, then appends version when it is missing,
+// so that it lines up with the port-based prefixes the services fall back to.
+func normalizeEndpoint(url, version, projectID string) string {
+	url = strings.TrimRight(url, "/")
+	if projectID != "" {
+		url = strings.TrimSuffix(url, "/"+projectID)
+	}
+	for _, placeholder := range []string{"/%(project_id)s", "/%(tenant_id)s", "/$(project_id)s", "/$(tenant_id)s"} {
+		url = strings.TrimSuffix(url, placeholder)
+	}
+	if version != "" && !strings.HasSuffix(url, "/"+version) {
+		url = url + "/" + version
+	}
+	return url
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"go-openstackclient/configs"
+	"go-openstackclient/internal/entity"
+	"testing"
+)
+
+func TestNormalizeEndpoint(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		version    string
+		projectID  string
+		want       string
+	}{
+		{"bare", "http://ctl:9696", "v2.0", "", "http://ctl:9696/v2.0"},
+		{"trailing slash", "http://ctl:9696/", "v2.0", "", "http://ctl:9696/v2.0"},
+		{"versioned", "http://ctl:8774/v2.1/", "v2.1", "", "http://ctl:8774/v2.1"},
+		{"project id", "http://ctl:8776/v3/p1", "v3", "p1", "http://ctl:8776/v3"},
+		{"project placeholder", "http://ctl:8776/v3/%(project_id)s", "v3", "p1", "http://ctl:8776/v3"},
+		{"tenant placeholder", "http://ctl:8776/v3/$(tenant_id)s", "v3", "", "http://ctl:8776/v3"},
+		{"no version", "https://ctl/image/", "", "", "https://ctl/image"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeEndpoint(tt.url, tt.version, tt.projectID); got != tt.want {
+				t.Errorf("normalizeEndpoint(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalogURL(t *testing.T) {
+	entries := []entity.CatalogEntry{
+		{Type: "network", Endpoints: []entity.Endpoint{
+			{Interface: "internal", Region: "RegionOne", Url: "http://internal:9696"},
+			{Interface: "public", Region: "RegionOne", Url: "http://public-one:9696"},
+			{Interface: "public", RegionId: "RegionTwo", Url: "http://public-two:9696"},
+		}},
+		{Type: "block-storage", Endpoints: []entity.Endpoint{
+			{Interface: "public", Region: "RegionOne", Url: "http://public-one:8776/v3/p1"},
+		}},
+	}
+	tests := []struct {
+		name          string
+		iface         string
+		region        string
+		version       string
+		serviceTypes  []string
+		want          string
+		found         bool
+	}{
+		{"default interface", "", "", "v2.0", []string{"network"}, "http://public-one:9696/v2.0", true},
+		{"internal", "internal", "", "v2.0", []string{"network"}, "http://internal:9696/v2.0", true},
+		{"region", "public", "RegionTwo", "v2.0", []string{"network"}, "http://public-two:9696/v2.0", true},
+		{"unknown region", "public", "RegionThree", "v2.0", []string{"network"}, "", false},
+		{"fallback type", "public", "", "v3", []string{"volumev3", "block-storage"}, "http://public-one:8776/v3", true},
+		{"unknown type", "public", "", "v2.1", []string{"compute"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c Catalog
+			c.set(entries, "p1", &configs.Openstack{Interface: tt.iface, Region: tt.region})
+			got, found := c.URL(tt.version, tt.serviceTypes...)
+			if got != tt.want || found != tt.found {
+				t.Errorf("URL(%v) = %q, %v, want %q, %v", tt.serviceTypes, got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestNilCatalogURL(t *testing.T) {
+	var c *Catalog
+	if got, found := c.URL("v2.0", "network"); found || got != "" {
+		t.Errorf("URL() on a nil catalog = %q, %v", got, found)
+	}
+}
@@ -13,17 +13,21 @@ var maxEfficientQOS string
 var maxSSDEfficientQOS string
 var supportedCinderResourceTypes = map[string]struct{}{
 	consts.VOLUME: struct{}{}, consts.SNAPSHOT: struct{}{},
+	consts.VOLUMETYPE: struct{}{}, consts.QOSSPEC: struct{}{},
+	consts.ATTACHMENT: struct{}{}, consts.VOLUMEQUOTA: struct{}{},
 }
 
 type Cinder struct {
 	client          client.Client
 	httpPrefix      string
+	catalog         *Catalog
 }
 
-func newCinder() *Cinder {
+func newCinder(conf *configs.Openstack, catalog *Catalog, opts ...client.ClientOption) *Cinder {
 	return &Cinder{
-		client: client.NewClient(),
-		httpPrefix: fmt.Sprintf("http://%s:%d/v3/", configs.CONF.Host, consts.CINDER),
+		client: client.NewClient(opts...),
+		httpPrefix: fmt.Sprintf("http://%s:%d/v3", conf.Host, consts.CinderPort),
+		catalog: catalog,
 	}
 }
 
@@ -32,8 +36,13 @@ func (c *Cinder) SupportedResources() map[string]struct{} {
 }
 
 
+// HttpPrefix resolves the endpoint from the keystone catalog, falling back
+// to the port-based url on the configured host.
 func (c *Cinder) HttpPrefix() string {
-	return fmt.Sprintf("http://%s:%d/v3", configs.CONF.Host, consts.CinderPort)
+	if url, ok := c.catalog.URL("v3", consts.VolumeService, consts.BlockStorageService); ok {
+		return url
+	}
+	return c.httpPrefix
 }
 
 func (c *Cinder) Client() client.Client {
@@ -43,12 +52,13 @@ func (c *Cinder) Client() client.Client {
 	return c.client
 }
 
-func (c *Cinder) Call(req client.Request) *fasthttp.Response {
+func (c *Cinder) Call(ctx context.Context, req client.Request) (*fasthttp.Response, error) {
 	resp := fasthttp.AcquireResponse()
 	req.Headers()["Openstack-Api-Version"] = "volume 3.59"
-	err := c.client.Call(context.Background(), req, resp)
+	err := c.client.Call(ctx, req, resp)
 	if err != nil {
-		panic(err)
+		fasthttp.ReleaseResponse(resp)
+		return nil, client.WithService(err, consts.CINDER)
 	}
-	return resp
+	return resp, nil
 }
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"go-openstackclient/configs"
 	"go-openstackclient/consts"
 	"go-openstackclient/utils"
 	"log"
@@ -17,30 +19,44 @@ type Cleaner struct {
 	wg                             sync.WaitGroup
 }
 
-func initProjectRunners(controller *Controller, projects []string) []*ProjectRunner {
-	toDeleteProjects := checkProjectExist(controller, projects)
+func initProjectRunners(ctx context.Context, controller *Controller, projects []string) []*ProjectRunner {
+	toDeleteProjects := checkProjectExist(ctx, controller, projects)
 	runners := make([]*ProjectRunner, 0)
 	for _, projectName := range toDeleteProjects {
-		projectRunner := NewProjectRunner(projectName)
+		projectRunner := NewProjectRunner(projectName, WithConfig(controller.config()))
 		runners = append(runners, projectRunner)
 	}
 	return runners
 }
 
-func NewCleaner(projects []string) *Cleaner {
-	adminManager := &Controller{
-		projectName: consts.ADMIN,
+// adminAuth scopes the cloud admin to projectName, the cleaner has to see
+// and delete resources of users it has no credentials for.
+func adminAuth(conf *configs.Openstack, projectName string) AuthPlugin {
+	return &PasswordAuth{
+		UserName: consts.ADMIN,
+		UserDomainName: conf.UserDomainName,
+		Password: conf.AdminPassword,
+		Scope: AuthScope{ProjectName: projectName, ProjectDomainName: conf.ProjectDomainName},
 	}
+}
+
+// NewCleaner cleans projects of the cloud in configs.CONF, or of the profile
+// given with WithConfig.
+func NewCleaner(ctx context.Context, projects []string, opts ...ControllerOption) *Cleaner {
+	adminManager := newAdminController(consts.ADMIN, opts...)
 	return &Cleaner{
 		adminManager: adminManager,
-		runners: initProjectRunners(adminManager, projects),
+		runners: initProjectRunners(ctx, adminManager, projects),
 	}
 }
 
-func checkProjectExist(controller *Controller, projects []string) []string {
+func checkProjectExist(ctx context.Context, controller *Controller, projects []string) []string {
 	toDeleteProjects := make([]string, 0)
 	for _, projectName := range projects {
-		if controller.GetProjectId(projectName) == "" {
+		projectId, err := controller.GetProjectId(ctx, projectName)
+		if err != nil {
+			log.Printf("@@@@@@@@@@@@@@@Get project %s failed, not to delete resources: %v\n", projectName, err)
+		} else if projectId == "" {
 		    log.Printf("@@@@@@@@@@@@@@@Project %s not exist, not to delete resources\n", projectName)
 		} else {
 			toDeleteProjects = append(toDeleteProjects, projectName)
@@ -49,10 +65,10 @@ func checkProjectExist(controller *Controller, projects []string) []string {
 	return toDeleteProjects
 }
 
-func (c *Cleaner) Run() {
+func (c *Cleaner) Run(ctx context.Context) {
 	for _, runner := range c.runners {
     	c.wg.Add(1)
-    	go runner.Run(&c.wg)
+    	go runner.Run(ctx, &c.wg)
 	}
 	c.wg.Wait()
     c.report()
@@ -71,8 +87,18 @@ type ProjectRunner struct {
 	completedChannel   chan struct{}
 }
 
-func NewProjectRunner(projectName string) *ProjectRunner {
-	m := NewController(projectName)
+// newAdminController builds a controller with the options, authenticating as
+// the admin of the profile they select unless WithAuth was given.
+func newAdminController(projectName string, opts ...ControllerOption) *Controller {
+	m := NewController(projectName, opts...)
+	if m.auth == nil {
+		m.auth = adminAuth(&m.config().Openstack, projectName)
+	}
+	return m
+}
+
+func NewProjectRunner(projectName string, opts ...ControllerOption) *ProjectRunner {
+	m := newAdminController(projectName, opts...)
 	depNodes := InitNodes()
 	return &ProjectRunner{
 		projectName: projectName,
@@ -91,7 +117,7 @@ func (p *ProjectRunner) getMethodName(resourceType string) string {
 	return res
 }
 
-func (p *ProjectRunner) callNode(node *Node) {
+func (p *ProjectRunner) callNode(ctx context.Context, node *Node) {
 	methodName := p.getMethodName(node.resourceType)
 	defer func() {
 		if err := recover(); err != nil {
@@ -105,22 +131,35 @@ func (p *ProjectRunner) callNode(node *Node) {
 		p.completedChannel <- struct{}{}
 	}()
 
-	if len(node.monitorDeleteChannel) != cap(node.monitorDeleteChannel) {
-		for len(node.monitorDeleteChannel) != cap(node.monitorDeleteChannel) {}
+	for len(node.monitorDeleteChannel) != cap(node.monitorDeleteChannel) {
+		select {
+		case <-ctx.Done():
+			log.Printf("%s cancelled before cleaning: %v", node.resourceType, ctx.Err())
+			return
+		case <-time.After(100 * time.Millisecond):
+		}
 	}
 	log.Printf("Cleaning %s is in progress", node.resourceType)
-	reflect.ValueOf(p.manager).MethodByName(methodName).Call([]reflect.Value{})
+	results := reflect.ValueOf(p.manager).MethodByName(methodName).Call([]reflect.Value{reflect.ValueOf(ctx)})
+	if len(results) != 0 && !results[0].IsNil() {
+		log.Printf("%s %s failed: %v", node.resourceType, methodName, results[0].Interface())
+	}
 }
 
-func (p *ProjectRunner) Run(wg *sync.WaitGroup) {
+func (p *ProjectRunner) Run(ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	for resourceType, _ := range p.depNodes {
-		go p.callNode(p.depNodes[resourceType])
+		go p.callNode(ctx, p.depNodes[resourceType])
 	}
 
 	for len(p.completedChannel) != cap(p.completedChannel) {
-		time.Sleep(2 * time.Second)
+		select {
+		case <-ctx.Done():
+			log.Printf("@@@@@@@@@@@@@@@Clean project %s cancelled: %v", p.projectName, ctx.Err())
+			return
+		case <-time.After(2 * time.Second):
+		}
 		log.Println("waiting for completed...")
 	}
 	//p.manager.DeleteUserByName(p.projectName)
@@ -146,10 +185,12 @@ func (p *ProjectRunner) makeReport() {
 	outputs := p.manager.DeleteChannels
 	reporters := make(map[string]reporter)
 	for resourceType, ch := range outputs {
+		// a cancelled run may leave delete goroutines still reporting, so
+		// drain what has arrived instead of closing the channel under them
 		totals := len(ch)
-		close(ch)
 		failed, succeed := make([]Output, 0), make([]map[string]string, 0)
-		for output := range ch {
+		for i := 0; i < totals; i++ {
+			output := <-ch
 			if !output.Success {
 				failed = append(failed, output)
 			} else {
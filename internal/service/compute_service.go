@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/valyala/fasthttp"
+	"go-openstackclient/consts"
+	"go-openstackclient/internal/entity"
+	"log"
+	"net/url"
+	"sync"
+)
+
+type ComputeServiceClient = ResourceClient[entity.ComputeService, entity.CreateUpdateOptions, *entity.UpdateComputeServiceOpts]
+
+// ComputeServices is admin only, nova returns every service in one page.
+func (s *Controller) ComputeServices() *ComputeServiceClient {
+	return NewResourceClient[entity.ComputeService, entity.CreateUpdateOptions, *entity.UpdateComputeServiceOpts](s, ResourceSpec{
+		Resource: consts.COMPUTESERVICE, Collection: consts.OSSERVICES,
+		Key: consts.COMPUTESERVICE, PluralKey: consts.COMPUTESERVICES, Paging: MarkerPaging})
+}
+
+// ListComputeServices lists the services of binary on host, every one when
+// they are empty.
+func (s *Controller) ListComputeServices(ctx context.Context, binary, host string) ([]entity.ComputeService, error) {
+	query := ""
+	if binary != "" {
+		query = joinQuery(query, "binary="+url.QueryEscape(binary))
+	}
+	if host != "" {
+		query = joinQuery(query, "host="+url.QueryEscape(host))
+	}
+	return s.ComputeServices().List(ctx, query)
+}
+
+// computeServiceOf returns the nova-compute service of host.
+func (s *Controller) computeServiceOf(ctx context.Context, host string) (entity.ComputeService, error) {
+	services, err := s.ListComputeServices(ctx, consts.NOVACOMPUTE, host)
+	if err != nil {
+		return entity.ComputeService{}, err
+	}
+	if len(services) == 0 {
+		return entity.ComputeService{}, fmt.Errorf("%s of host %s: %w", consts.NOVACOMPUTE, host, ErrNotFound)
+	}
+	return services[0], nil
+}
+
+func (s *Controller) EnableComputeService(ctx context.Context, serviceId string) (entity.ComputeService, error) {
+	return s.ComputeServices().Update(ctx, serviceId, &entity.UpdateComputeServiceOpts{Status: "enabled"})
+}
+
+// DisableComputeService stops the scheduler from placing servers on the
+// service's host, reason is kept as the disabled reason.
+func (s *Controller) DisableComputeService(ctx context.Context, serviceId, reason string) (entity.ComputeService, error) {
+	return s.ComputeServices().Update(ctx, serviceId, &entity.UpdateComputeServiceOpts{
+		Status: "disabled", DisabledReason: reason})
+}
+
+// ForceDownComputeService marks the service down without waiting for its
+// heartbeat to expire, so that the servers of a fenced host can be
+// evacuated. Pass false once the host is back.
+func (s *Controller) ForceDownComputeService(ctx context.Context, serviceId string, down bool) (entity.ComputeService, error) {
+	return s.ComputeServices().Update(ctx, serviceId, &entity.UpdateComputeServiceOpts{ForcedDown: &down})
+}
+
+// DeleteComputeService removes the service and its compute node, nova
+// refuses while the host still has servers.
+func (s *Controller) DeleteComputeService(ctx context.Context, serviceId string) error {
+	return s.ComputeServices().Delete(ctx, serviceId)
+}
+
+func (s *Controller) ListHypervisors(ctx context.Context, opts ...ListOption) (entity.Hypervisors, error) {
+	var hypervisors entity.Hypervisors
+	if err := s.listAll(ctx, s.newPager(&ExtraOption{
+		Resource: consts.HYPERVISOR, ResourceLocation: fmt.Sprintf("%s/detail", consts.OSHYPERVISORS)},
+		consts.HYPERVISORS, MarkerPaging, opts), &hypervisors); err != nil {
+		return hypervisors, err
+	}
+	log.Println("==============List hypervisors success, there had", hypervisors.Count)
+	return hypervisors, nil
+}
+
+// GetHypervisorStatistics sums the resources of every hypervisor.
+func (s *Controller) GetHypervisorStatistics(ctx context.Context) (entity.HypervisorStatistics, error) {
+	var statistics entity.HypervisorStatisticsMap
+	resp, err := s.wrapper(constructListRequestOpts)(ctx, nil, &ExtraOption{
+		Resource: consts.HYPERVISOR, ResourceLocation: fmt.Sprintf("%s/statistics", consts.OSHYPERVISORS)})
+	if err != nil {
+		return statistics.HypervisorStatistics, err
+	}
+	defer fasthttp.ReleaseResponse(resp)
+
+	err = json.Unmarshal(resp.Body(), &statistics)
+	return statistics.HypervisorStatistics, err
+}
+
+// listHostServers lists the servers of every project running on host.
+func (s *Controller) listHostServers(ctx context.Context, host string) (entity.Servers, error) {
+	var servers entity.Servers
+	err := s.listAll(ctx, s.newPager(&ExtraOption{
+		Resource: consts.SERVER, ResourceLocation: fmt.Sprintf("%s/detail", consts.SERVERS),
+		ResourceSuffix: "all_tenants=true&host=" + url.QueryEscape(host)},
+		consts.SERVERS, MarkerPaging, nil), &servers)
+	return servers, err
+}
+
+// DrainFailure is a server DrainHost could not move.
+type DrainFailure struct {
+	ServerId          string
+	Name              string
+	Status            string
+	Err               error
+}
+
+type DrainReport struct {
+	Host              string
+	ServiceId         string
+	Migrated          []string
+	Failed            []DrainFailure
+}
+
+// DrainHost disables the compute service of host with reason and moves its
+// servers away, at most concurrency at a time. Running servers are live
+// migrated, stopped ones cold migrated and confirmed, the others can't
+// move and are reported as failed like the migrations that went wrong.
+func (s *Controller) DrainHost(ctx context.Context, host, reason string, concurrency int) (DrainReport, error) {
+	report := DrainReport{Host: host}
+	service, err := s.computeServiceOf(ctx, host)
+	if err != nil {
+		return report, err
+	}
+	report.ServiceId = service.Id
+	if _, err = s.DisableComputeService(ctx, service.Id, reason); err != nil {
+		return report, err
+	}
+	servers, err := s.listHostServers(ctx, host)
+	if err != nil {
+		return report, err
+	}
+
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		tokens = make(chan struct{}, concurrency)
+	)
+	for _, server := range servers.Servers {
+		tempServer := server
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tokens <- struct{}{}
+			defer func() { <-tokens }()
+
+			err := s.migrateOffHost(ctx, tempServer, host)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				report.Failed = append(report.Failed, DrainFailure{
+					ServerId: tempServer.Id, Name: tempServer.Name, Status: tempServer.Status, Err: err})
+				return
+			}
+			report.Migrated = append(report.Migrated, tempServer.Id)
+		}()
+	}
+	wg.Wait()
+	log.Printf("==============Drain host %s completed, migrated %d, failed %d",
+		host, len(report.Migrated), len(report.Failed))
+	return report, nil
+}
+
+func (s *Controller) migrateOffHost(ctx context.Context, server entity.Server, host string) error {
+	var err error
+	switch server.Status {
+	case consts.ACTIVE:
+		err = s.LiveMigrateServer(ctx, server.Id, "", nil)
+	case consts.SHUTOFF:
+		err = s.MigrateServer(ctx, server.Id, "", true)
+	default:
+		return fmt.Errorf("server %s can't be migrated in status %s", server.Id, server.Status)
+	}
+	if err != nil {
+		return err
+	}
+	moved, err := s.GetInstanceDetail(ctx, server.Id)
+	if err != nil {
+		return err
+	}
+	if moved.OSEXTSRVATTRHost == host {
+		return fmt.Errorf("server %s is still on host %s", server.Id, host)
+	}
+	return nil
+}
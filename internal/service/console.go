@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/valyala/fasthttp"
+	"go-openstackclient/consts"
+	"go-openstackclient/internal/client"
+	"go-openstackclient/internal/entity"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// legacyConsoleActions are the console actions nova had before the
+// remote-consoles API of microversion 2.6, MKS consoles came only with it.
+var legacyConsoleActions = map[entity.ConsoleProtocol]string{
+	entity.ConsoleProtocolVNC: "os-getVNCConsole",
+	entity.ConsoleProtocolSPICE: "os-getSPICEConsole",
+	entity.ConsoleProtocolRDP: "os-getRDPConsole",
+	entity.ConsoleProtocolSerial: "os-getSerialConsole",
+}
+
+// ComputeMaxMicroversion reads the highest microversion the compute API
+// supports from its version document.
+func (s *Controller) ComputeMaxMicroversion(ctx context.Context) (string, error) {
+	resp, err := s.Do(ctx, RequestOption{
+		Action: GET,
+		Resource: consts.SERVER,
+		Headers: make(map[string]string),
+	})
+	if err != nil {
+		return "", err
+	}
+	defer fasthttp.ReleaseResponse(resp)
+
+	var document struct {
+		Version struct {
+			Version     string `json:"version"`
+		} `json:"version"`
+	}
+	if err = json.Unmarshal(resp.Body(), &document); err != nil {
+		return "", err
+	}
+	return document.Version.Version, nil
+}
+
+// microversionAtLeast compares microversions like 2.6 and 2.74 part by part,
+// an unknown version is taken for the newest.
+func microversionAtLeast(version, minimum string) bool {
+	if version == "" {
+		return true
+	}
+	v, m := strings.SplitN(version, ".", 2), strings.SplitN(minimum, ".", 2)
+	if len(v) != 2 || len(m) != 2 {
+		return true
+	}
+	for i := range v {
+		a, errA := strconv.Atoi(v[i])
+		b, errB := strconv.Atoi(m[i])
+		if errA != nil || errB != nil {
+			return true
+		}
+		if a != b {
+			return a > b
+		}
+	}
+	return true
+}
+
+// CreateRemoteConsole returns a console url of the server. Clouds older than
+// microversion 2.6, or refusing the remote-consoles API, are asked through
+// the legacy os-get<Protocol>Console actions.
+func (s *Controller) CreateRemoteConsole(ctx context.Context, serverId string, protocol entity.ConsoleProtocol, consoleType entity.ConsoleType) (entity.RemoteConsoleMap, error) {
+	var console entity.RemoteConsoleMap
+	microversion := "2.6"
+	if protocol == entity.ConsoleProtocolMKS {
+		microversion = "2.8"
+	}
+
+	maxVersion, err := s.ComputeMaxMicroversion(ctx)
+	if err != nil {
+		log.Println("==============Get compute microversion failed, try remote consoles", err)
+	}
+	if microversionAtLeast(maxVersion, microversion) {
+		resp, err := s.Do(ctx, RequestOption{
+			Action: CREATE,
+			Resource: consts.SERVER,
+			ResourceLocation: fmt.Sprintf("%s/%s/remote-consoles", consts.SERVERS, serverId),
+			Body: &entity.CreateConsoleOpts{Protocol: protocol, Type: consoleType},
+			Headers: map[string]string{consts.OpenStackAPIVersion: "compute " + microversion},
+		})
+		if err == nil {
+			defer fasthttp.ReleaseResponse(resp)
+			if err = json.Unmarshal(resp.Body(), &console); err != nil {
+				return console, err
+			}
+			log.Println("==============Create remote console success", serverId, console.RemoteConsole.Url)
+			return console, nil
+		}
+		status := client.StatusCode(err)
+		if status != fasthttp.StatusNotFound && status != fasthttp.StatusNotAcceptable {
+			return console, err
+		}
+		log.Println("==============Remote consoles refused, fall back to the legacy console action", err)
+	}
+	return s.createLegacyConsole(ctx, serverId, protocol, consoleType)
+}
+
+func (s *Controller) createLegacyConsole(ctx context.Context, serverId string, protocol entity.ConsoleProtocol, consoleType entity.ConsoleType) (entity.RemoteConsoleMap, error) {
+	var console entity.RemoteConsoleMap
+	action, ok := legacyConsoleActions[protocol]
+	if !ok {
+		return console, fmt.Errorf("%s consoles need compute microversion 2.8", protocol)
+	}
+	body, err := s.serverActionAt(ctx, serverId, "2.1", &entity.LegacyConsoleOpts{Action: action, Type: consoleType})
+	if err != nil {
+		return console, err
+	}
+	var legacy entity.LegacyConsoleMap
+	if err = json.Unmarshal(body, &legacy); err != nil {
+		return console, err
+	}
+	console.RemoteConsole.Protocol = string(protocol)
+	console.RemoteConsole.Type = legacy.Console.Type
+	console.RemoteConsole.Url = legacy.Console.Url
+	log.Println("==============Create legacy console success", serverId, console.RemoteConsole.Url)
+	return console, nil
+}
+
+// GetConsoleOutput returns the last lines of the console log of the server,
+// the whole log when lines is not positive.
+func (s *Controller) GetConsoleOutput(ctx context.Context, serverId string, lines int) (string, error) {
+	opts := &entity.ConsoleOutputOpts{}
+	if lines > 0 {
+		opts.Length = &lines
+	}
+	body, err := s.serverAction(ctx, serverId, opts)
+	if err != nil {
+		return "", err
+	}
+	var output struct {
+		Output      string `json:"output"`
+	}
+	if err = json.Unmarshal(body, &output); err != nil {
+		return "", err
+	}
+	return output.Output, nil
+}
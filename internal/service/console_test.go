@@ -0,0 +1,29 @@
+package service
+
+import "testing"
+
+func TestMicroversionAtLeast(t *testing.T) {
+	tests := []struct {
+		version  string
+		minimum  string
+		want     bool
+	}{
+		{"2.6", "2.6", true},
+		{"2.74", "2.6", true},
+		{"2.10", "2.9", true},
+		{"2.5", "2.6", false},
+		{"2.1", "2.10", false},
+		{"3.0", "2.99", true},
+		{"1.99", "2.0", false},
+		{"", "2.6", true},
+		{"latest", "2.6", true},
+		{"2.x", "2.6", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version+">="+tt.minimum, func(t *testing.T) {
+			if got := microversionAtLeast(tt.version, tt.minimum); got != tt.want {
+				t.Errorf("microversionAtLeast(%q, %q) = %v, want %v", tt.version, tt.minimum, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/valyala/fasthttp"
@@ -9,7 +10,7 @@ import (
 	"go-openstackclient/internal/client"
 	"go-openstackclient/internal/entity"
 	"log"
-	"reflect"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
@@ -24,172 +25,396 @@ var actionMapMethod = map[string]string{
 }
 
 type Controller struct {
-	neutron           *Neutron
-	keystone          *Keystone
-	nova              *Nova
-	cinder            *Cinder
+	services          *ServiceRegistry
+	instances         map[string]Service
+	servicesMu        sync.Mutex
+	conf              *configs.Server
+	clientOpts        []client.ClientOption
+	auth              AuthPlugin
 	token             string
+	expiresAt         time.Time
+	tokenMu           sync.Mutex
+	catalog           Catalog
 	projectName       string
 	projectID         string
+	projectMu         sync.Mutex
 	DeleteChannels    map[string]chan Output
 	mu                sync.Mutex
 }
 
-func NewController(projectName string) *Controller {
-    return &Controller{
+// ControllerOption customises a Controller built by NewController.
+type ControllerOption func(*Controller)
+
+// WithConfig makes the controller use conf, e.g. a profile of a
+// configs.Registry, instead of the process-wide configs.CONF.
+func WithConfig(conf *configs.Server) ControllerOption {
+	return func(s *Controller) {
+		s.conf = conf
+	}
+}
+
+// WithClientOptions configures the clients of the controller's services,
+// e.g. client.WithRetryPolicy.
+func WithClientOptions(opts ...client.ClientOption) ControllerOption {
+	return func(s *Controller) {
+		s.clientOpts = append(s.clientOpts, opts...)
+	}
+}
+
+// WithServiceRegistry routes requests through registry instead of the
+// built-in services, e.g. a DefaultServiceRegistry with more registered.
+func WithServiceRegistry(registry *ServiceRegistry) ControllerOption {
+	return func(s *Controller) {
+		s.services = registry
+	}
+}
+
+// WithAuth makes the controller authenticate with plugin instead of the
+// plugin configured in its profile.
+func WithAuth(plugin AuthPlugin) ControllerOption {
+	return func(s *Controller) {
+		s.auth = plugin
+	}
+}
+
+func NewController(projectName string, opts ...ControllerOption) *Controller {
+    s := &Controller{
     	projectName: projectName,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// NewControllerFromProfile builds a controller for the named profile of
+// registry, the default profile when profile is empty.
+func NewControllerFromProfile(registry *configs.Registry, profile, projectName string, opts ...ControllerOption) (*Controller, error) {
+	conf, err := registry.Get(profile)
+	if err != nil {
+		return nil, err
+	}
+	return NewController(projectName, append([]ControllerOption{WithConfig(conf)}, opts...)...), nil
+}
+
+// config returns the profile the controller was built with, configs.CONF
+// when none was given.
+func (s *Controller) config() *configs.Server {
+	if s.conf == nil {
+		return &configs.CONF
+	}
+	return s.conf
+}
+
+// Config is the profile the controller works on, e.g. to resolve the
+// "local" placeholders of the entity AssignProps builders.
+func (s *Controller) Config() *configs.Server {
+	return s.config()
+}
+
+// authPlugin returns the controller's credentials, building them from
+// its profile scoped to the controller's project the first time when none
+// were given.
+func (s *Controller) authPlugin() (AuthPlugin, error) {
+	if s.auth == nil {
+		conf := s.config().Openstack
+		if s.projectName != "" {
+			conf.ProjectId, conf.ProjectName = "", s.projectName
+		}
+		plugin, err := NewAuthPlugin(conf)
+		if err != nil {
+			return nil, err
+		}
+		s.auth = plugin
+	}
+	return s.auth, nil
 }
 
-func wrapper(fn func(options entity.CreateUpdateOptions, extraOpts *ExtraOption) RequestOption) func(options entity.CreateUpdateOptions, extraOption *ExtraOption) *fasthttp.Response {
-	return func(param entity.CreateUpdateOptions, extraOpt *ExtraOption) *fasthttp.Response {
+// wrapper binds a request constructor to the controller, so the request is
+// sent with this controller's credentials and endpoints.
+func (s *Controller) wrapper(fn func(options entity.CreateUpdateOptions, extraOpts *ExtraOption) RequestOption) func(ctx context.Context, options entity.CreateUpdateOptions, extraOption *ExtraOption) (*fasthttp.Response, error) {
+	return func(ctx context.Context, param entity.CreateUpdateOptions, extraOpt *ExtraOption) (*fasthttp.Response, error) {
 		opts := fn(param, extraOpt)
-		resp := defaultController.Do(opts)
-		return resp
+		return s.Do(ctx, opts)
 	}
 }
 
-func (s *Controller) GetProjectId(projectName string) string {
+func (s *Controller) GetProjectId(ctx context.Context, projectName string) (string, error) {
 	opts := &ExtraOption{
 		Resource: consts.PROJECT, ResourceLocation: consts.PROJECTS,
 		ResourceSuffix: fmt.Sprintf("name=%s", projectName),
 	}
-	res := wrapper(constructListRequestOpts)(nil, opts)
+	res, err := s.wrapper(constructListRequestOpts)(ctx, nil, opts)
+	if err != nil {
+		return "", err
+	}
 	defer fasthttp.ReleaseResponse(res)
 
 	var projects entity.Projects
-	_ = json.Unmarshal(res.Body(), &projects)
+	if err = json.Unmarshal(res.Body(), &projects); err != nil {
+		return "", err
+	}
 	if len(projects.Ps) == 0 {
-		return ""
+		return "", nil
 	}
-	return projects.Ps[0].Id
+	return projects.Ps[0].Id, nil
 }
 
-func (s *Controller) Project() string {
-    if len(s.projectID) == 0 {
-    	s.projectID = s.GetProjectId(s.projectName)
+func (s *Controller) Project(ctx context.Context) (string, error) {
+	if projectID := s.currentProjectID(); len(projectID) != 0 {
+		return projectID, nil
+	}
+	projectID, err := s.GetProjectId(ctx, s.projectName)
+	if err != nil {
+		return "", err
 	}
+	s.setProjectID(projectID)
+	return projectID, nil
+}
+
+// currentProjectID is the project id resolved so far, empty until the first
+// token or Project call.
+func (s *Controller) currentProjectID() string {
+	s.projectMu.Lock()
+	defer s.projectMu.Unlock()
 	return s.projectID
 }
 
-func (s *Controller) Do(option RequestOption) *fasthttp.Response {
-	req := s.buildRequest(option)
-    service := s.targetService(option)
-
-    resp := service.Call(req)
-    return resp
-}
-
-func (s *Controller) Token() string {
-	if len(s.token) == 0 {
-		createOpts := &entity.AuthOption{
-			Auth: entity.Auth{
-				Identity: entity.Identity{
-					Methods: []string{"password"},
-					Password: entity.Password{
-						Userr: entity.Userr{
-							Name: configs.CONF.UserName,
-							Password: configs.CONF.UserPassword,
-							Domain: entity.Domain{Name: "default"},
-						},
-					},
-				},
-				Scope: entity.Scope{
-					Projectt: entity.Projectt{
-						Name: configs.CONF.ProjectName,
-						Domain: entity.Domain{Name: "default"},
-					},
-				},
-			},
-		}
-		opts := RequestOption{
-			Action: CREATE,
-			Resource: consts.TOKEN,
-			ResourceLocation: "auth/tokens",
-			RequestSuffix: "",
-			Body: createOpts,
-			Headers: make(map[string]string),
-		}
-		resp := defaultController.Do(opts)
-		defer fasthttp.ReleaseResponse(resp)
+// setProjectID records projectID unless another goroutine resolved it first.
+func (s *Controller) setProjectID(projectID string) {
+	s.projectMu.Lock()
+	defer s.projectMu.Unlock()
+	if s.projectID == "" {
+		s.projectID = projectID
+	}
+}
 
-		token := string(resp.Header.Peek("X-Subject-Token"))
-		log.Println("==============Get auth token success")
-		s.token = token
+// projectFilter is the query restricting a list to the controller's project,
+// the admin controller lists every project.
+func (s *Controller) projectFilter(ctx context.Context) (string, error) {
+	if s.projectName == consts.ADMIN {
+		return "", nil
 	}
-	return s.token
+	projectID, err := s.Project(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("project_id=%s", projectID), nil
 }
 
-func (s *Controller) buildRequest(option RequestOption) client.Request {
-	method := s.actionMapMethod(option.Action)
-	endpoint := s.getEndpoint(option)
-	service := s.targetService(option)
+// projectPath prefixes the cinder path with the id of the controller's
+// project, which is looked up when no token has been issued yet.
+func (s *Controller) projectPath(ctx context.Context, format string, a ...interface{}) (string, error) {
+	projectID, err := s.Project(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s", projectID, fmt.Sprintf(format, a...)), nil
+}
+
+// Do sends the request with the current token. A 401 means the token was
+// revoked or expired early, so it is refreshed once and the request replayed.
+func (s *Controller) Do(ctx context.Context, option RequestOption) (*fasthttp.Response, error) {
+	if option.Resource == consts.TOKEN {
+		return s.do(ctx, option, "")
+	}
+	token, err := s.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(ctx, option, token)
+	if client.StatusCode(err) != fasthttp.StatusUnauthorized {
+		return resp, err
+	}
+
+	log.Println("==============Token was rejected, re-authenticate and replay", option.Action, option.ResourceLocation)
+	s.invalidateToken(token)
+	if token, err = s.Token(ctx); err != nil {
+		return nil, err
+	}
+	return s.do(ctx, option, token)
+}
+
+func (s *Controller) do(ctx context.Context, option RequestOption, token string) (*fasthttp.Response, error) {
+	headers := make(map[string]string, len(option.Headers)+1)
+	for k, v := range option.Headers {
+		headers[k] = v
+	}
+	if token != "" {
+		headers[consts.AuthToken] = token
+	}
+	option.Headers = headers
+
+	req, err := s.buildRequest(option)
+	if err != nil {
+		return nil, err
+	}
+    service, err := s.targetService(option)
+	if err != nil {
+		return nil, err
+	}
+    return service.Call(ctx, req)
+}
+
+// Token returns a cached token, authenticating again when there is none or it
+// expires within consts.TokenRefreshMargin. Concurrent callers wait on the
+// same refresh instead of each requesting a token.
+func (s *Controller) Token(ctx context.Context) (string, error) {
+	s.tokenMu.Lock()
+	defer s.tokenMu.Unlock()
+
+	if len(s.token) != 0 && (s.expiresAt.IsZero() || time.Until(s.expiresAt) > consts.TokenRefreshMargin) {
+		return s.token, nil
+	}
+
+	plugin, err := s.authPlugin()
+	if err != nil {
+		return "", err
+	}
+	opts := RequestOption{
+		Action: CREATE,
+		Resource: consts.TOKEN,
+		ResourceLocation: "auth/tokens",
+		RequestSuffix: "",
+		Body: plugin.AuthOptions(),
+		Headers: make(map[string]string),
+	}
+	resp, err := s.Do(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+	defer fasthttp.ReleaseResponse(resp)
+
+	var tokenBody entity.TokenMap
+	if err = json.Unmarshal(resp.Body(), &tokenBody); err != nil {
+		return "", err
+	}
+	s.catalog.set(tokenBody.Catalog, tokenBody.Project.Id, &s.config().Openstack)
+	if tokenBody.Project.Name == s.projectName {
+		s.setProjectID(tokenBody.Project.Id)
+	}
+
+	s.token = string(resp.Header.Peek("X-Subject-Token"))
+	s.expiresAt = tokenBody.ExpiresAt
+	log.Println("==============Get auth token success, expires at", s.expiresAt)
+	return s.token, nil
+}
+
+// invalidateToken drops the cached token if it is still the rejected one, so
+// that goroutines racing on the same 401 trigger a single refresh.
+func (s *Controller) invalidateToken(stale string) {
+	s.tokenMu.Lock()
+	defer s.tokenMu.Unlock()
+	if s.token == stale {
+		s.token = ""
+		s.expiresAt = time.Time{}
+	}
+}
+
+func (s *Controller) buildRequest(option RequestOption) (client.Request, error) {
+	method, err := s.actionMapMethod(option.Action)
+	if err != nil {
+		return nil, err
+	}
+	service, err := s.targetService(option)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := s.getEndpoint(service, option)
 	var req client.Request
 	if option.Body != nil {
-		reqBody := option.Body.ToRequestBody()
+		reqBody, err := entity.RequestBody(option.Body)
+		if err != nil {
+			return nil, err
+		}
 		req = service.Client().NewRequest(
 			endpoint, method, consts.ContentTypeJson, option.Headers, reqBody)
 	} else {
 		req = service.Client().NewRequest(
 			endpoint, method, consts.ContentTypeJson, option.Headers, nil)
 	}
-	return req
+	return req, nil
 }
 
-func (s *Controller) targetService(option RequestOption) Service {
-	var ok bool
-    if _, ok = s.Neutron().SupportedResources()[option.Resource]; ok {
-    	return s.neutron
-	} else if _, ok = s.Keystone().SupportedResources()[option.Resource]; ok {
-		return s.keystone
-	} else if _, ok = s.Nova().SupportedResources()[option.Resource]; ok {
-		return s.nova
-	} else if _, ok = s.Cinder().SupportedResources()[option.Resource]; ok {
-		return s.cinder
-	} else {
+func (s *Controller) targetService(option RequestOption) (Service, error) {
+	name, ok := s.serviceRegistry().ServiceFor(option.Resource)
+	if !ok {
+		return nil, fmt.Errorf("resource type %q is not supported by any service", option.Resource)
+	}
+	return s.service(name), nil
+}
+
+func (s *Controller) serviceRegistry() *ServiceRegistry {
+	if s.services == nil {
+		return defaultServices
+	}
+	return s.services
+}
+
+// service returns the named service, building it on first use.
+func (s *Controller) service(name string) Service {
+	s.servicesMu.Lock()
+	defer s.servicesMu.Unlock()
+
+	if svc, ok := s.instances[name]; ok {
+		return svc
+	}
+	spec, ok := s.serviceRegistry().specs[name]
+	if !ok {
 		return nil
 	}
+	if s.instances == nil {
+		s.instances = make(map[string]Service)
+	}
+	opts := s.clientOpts
+	if caCert := s.config().CACert; caCert != "" {
+		opts = append([]client.ClientOption{client.WithCACertFile(caCert)}, opts...)
+	}
+	svc := spec.New(&s.config().Openstack, &s.catalog, opts...)
+	s.instances[name] = svc
+	return svc
 }
 
 func (s *Controller) Neutron() *Neutron {
-	if s.neutron == nil {
-		s.neutron = newNeutron()
-	}
-	return s.neutron
+	neutron, _ := s.service(consts.NEUTRON).(*Neutron)
+	return neutron
 }
 
 func (s *Controller) Nova() *Nova {
-	if s.nova == nil {
-		s.nova = newNova()
-	}
-	return s.nova
+	nova, _ := s.service(consts.NOVA).(*Nova)
+	return nova
 }
 
 func (s *Controller) Keystone() *Keystone {
-	if s.keystone == nil {
-		s.keystone = newKeystone()
-	}
-	return s.keystone
+	keystone, _ := s.service(consts.KEYSTONE).(*Keystone)
+	return keystone
 }
 
 func (s *Controller) Cinder() *Cinder {
-	if s.cinder == nil {
-		s.cinder = newCinder()
-	}
-	return s.cinder
+	cinder, _ := s.service(consts.CINDER).(*Cinder)
+	return cinder
+}
+
+func (s *Controller) Octavia() *Octavia {
+	octavia, _ := s.service(consts.OCTAVIA).(*Octavia)
+	return octavia
 }
 
-func (s *Controller) actionMapMethod(action string) string {
+func (s *Controller) Glance() *Glance {
+	glance, _ := s.service(consts.GLANCE).(*Glance)
+	return glance
+}
+
+func (s *Controller) actionMapMethod(action string) (string, error) {
 	if method, ok := actionMapMethod[action]; ok {
-		return method
-	} else {
-		panic("The action not supported")
+		return method, nil
 	}
+	return "", fmt.Errorf("the action %q is not supported", action)
 }
 
-func (s *Controller) getEndpoint(option RequestOption) string {
-     service := s.targetService(option)
-     url := fmt.Sprintf("%s/%s", service.HttpPrefix(), option.ResourceLocation)
+func (s *Controller) getEndpoint(service Service, option RequestOption) string {
+     url := fmt.Sprintf("%s/%s", strings.TrimSuffix(service.HttpPrefix(), "/"),
+     	strings.TrimPrefix(option.ResourceLocation, "/"))
      if len(option.RequestSuffix) != 0 {
      	url = fmt.Sprintf("%s?%s", url, option.RequestSuffix)
 	 }
@@ -201,365 +426,337 @@ func (s *Controller) MakeDeleteChannel(resourceType string, length int) chan Out
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.DeleteChannels == nil {
+		s.DeleteChannels = make(map[string]chan Output)
+	}
 	s.DeleteChannels[resourceType] = make(chan Output, length)
 	return s.DeleteChannels[resourceType]
 }
 
-// network
-
-func (s *Controller) CreateNetwork(opts entity.CreateUpdateOptions) entity.NetworkMap {
-	res := wrapper(constructNetworkRequestOpts)(opts, nil)
-	defer fasthttp.ReleaseResponse(res)
-	var network entity.NetworkMap
-	_ = json.Unmarshal(res.Body(), &network)
-
-	log.Println("==============Create internal network success", network.Network.Id)
-	return network
+// waitDeleteChannel blocks until every delete goroutine has reported into ch
+// or ctx is done.
+func waitDeleteChannel(ctx context.Context, ch chan Output) error {
+	for len(ch) != cap(ch) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	return nil
 }
 
-func (s *Controller) ListNetworks() entity.Networks {
-	var urlSuffix = ""
-	if s.projectName != consts.ADMIN {
-		urlSuffix = fmt.Sprintf("project_id=%s", s.Project())
+// deleteOutput fills the Output of a delete call from the response or error.
+func deleteOutput(outputObj Output, resp *fasthttp.Response, err error) Output {
+	if err != nil {
+		log.Println("catch error：", err)
+		outputObj.Success = false
+		outputObj.Response = err
+		return outputObj
 	}
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.NETWORK, ResourceLocation: consts.NETWORKS,
-		ResourceSuffix: urlSuffix})
 	defer fasthttp.ReleaseResponse(resp)
 
-	var networks entity.Networks
-	_ = json.Unmarshal(resp.Body(), &networks)
-	log.Println("==============List network success, there had", networks.Count)
-	return networks
+	outputObj.Response = resp.StatusCode()
+	outputObj.Success = true
+	return outputObj
 }
 
-func (s *Controller) DeleteNetwork(ipId string) Output {
-	outputObj := Output{ParametersMap: map[string]string{"network_id": ipId}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-			outputObj.Response = err
-		}
-	}()
+// resultOutput fills the Output of a delete made through a ResourceClient.
+func resultOutput(outputObj Output, err error) Output {
+	if err != nil {
+		log.Println("catch error：", err)
+		outputObj.Success = false
+		outputObj.Response = err
+		return outputObj
+	}
+	outputObj.Success = true
+	return outputObj
+}
 
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.NETWORK, ResourceLocation: fmt.Sprintf("%s/%s", consts.NETWORKS, ipId)})
-	defer fasthttp.ReleaseResponse(resp)
+// network
 
-	outputObj.Response = resp.StatusCode()
-	if resp.StatusCode() == fasthttp.StatusOK {
-		outputObj.Success = true
+func (s *Controller) CreateNetwork(ctx context.Context, opts *entity.CreateNetworkOpts) (entity.NetworkMap, error) {
+	network, err := s.Networks().Create(ctx, opts)
+	return entity.NetworkMap{Network: network}, err
+}
+
+func (s *Controller) ListNetworks(ctx context.Context, opts ...ListOption) (entity.Networks, error) {
+	query, err := s.projectFilter(ctx)
+	if err != nil {
+		return entity.Networks{}, err
 	}
-	return outputObj
+	networks, err := s.Networks().List(ctx, query, opts...)
+	if err != nil {
+		return entity.Networks{}, err
+	}
+	log.Println("==============List network success, there had", len(networks))
+	return entity.Networks{Nets: networks, Count: len(networks)}, nil
+}
+
+// IterNetworks streams the networks page by page.
+func (s *Controller) IterNetworks(ctx context.Context, opts ...ListOption) *Iterator[entity.Networks] {
+	return newIterator[entity.Networks](s.Networks().projectPager(ctx, opts))
+}
+
+func (s *Controller) DeleteNetwork(ctx context.Context, ipId string) Output {
+	outputObj := Output{ParametersMap: map[string]string{"network_id": ipId}}
+	return resultOutput(outputObj, s.Networks().Delete(ctx, ipId))
 }
 
-func (s *Controller) DeleteNetworks() {
-	networks := s.ListNetworks()
+func (s *Controller) DeleteNetworks(ctx context.Context) error {
+	networks, err := s.ListNetworks(ctx)
+	if err != nil {
+		return err
+	}
 	ch := s.MakeDeleteChannel(consts.NETWORK, len(networks.Nets))
 
 	for _, network := range networks.Nets {
 		tempNetwork := network
 		go func() {
-			ch <- s.DeleteNetwork(tempNetwork.Id)
+			ch <- s.DeleteNetwork(ctx, tempNetwork.Id)
 		}()
 	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("Networks were deleted completely")
+	return nil
 }
 
 // subnet
 
-func (s *Controller) CreateSubnet(opts entity.CreateUpdateOptions) entity.SubnetMap {
-	res := wrapper(constructSubnetRequestOpts)(opts, nil)
-	defer fasthttp.ReleaseResponse(res)
-	var subnet entity.SubnetMap
-	_ = json.Unmarshal(res.Body(), &subnet)
-
-	log.Println("==============Create internal subnet success", subnet.Id)
-	return subnet
+func (s *Controller) CreateSubnet(ctx context.Context, opts *entity.CreateSubnetOpts) (entity.SubnetMap, error) {
+	subnet, err := s.Subnets().Create(ctx, opts)
+	return entity.SubnetMap{Subnet: subnet}, err
 }
 
 
-func (s *Controller) ListSubnet() entity.Subnets {
-	var urlSuffix = ""
-	if s.projectName != consts.ADMIN {
-		urlSuffix = fmt.Sprintf("project_id=%s", s.projectID)
+func (s *Controller) ListSubnet(ctx context.Context, opts ...ListOption) (entity.Subnets, error) {
+	query, err := s.projectFilter(ctx)
+	if err != nil {
+		return entity.Subnets{}, err
+	}
+	subnets, err := s.Subnets().List(ctx, query, opts...)
+	if err != nil {
+		return entity.Subnets{}, err
 	}
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.SUBNET, ResourceLocation: consts.SUBNETS,
-		ResourceSuffix: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var subnets entity.Subnets
-	_ = json.Unmarshal(resp.Body(), &subnets)
 	log.Println("==============List subnet success")
-	return subnets
+	return entity.Subnets{Ss: subnets}, nil
 }
 
-func (s *Controller) DeleteSubnet(ipId string) Output {
+func (s *Controller) DeleteSubnet(ctx context.Context, ipId string) Output {
 	outputObj := Output{ParametersMap: map[string]string{"subnet_id": ipId}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-			outputObj.Response = err
-		}
-	}()
-
-	urlSuffix := fmt.Sprintf("%s/%s", consts.SUBNETS, ipId)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.NETWORK, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	outputObj.Response = resp.StatusCode()
-	if resp.StatusCode() == fasthttp.StatusOK {
-		outputObj.Success = true
-	}
-	return outputObj
+	return resultOutput(outputObj, s.Subnets().Delete(ctx, ipId))
 }
 
-func (s *Controller) DeleteSubnets() {
-	subnets := s.ListSubnet()
+func (s *Controller) DeleteSubnets(ctx context.Context) error {
+	subnets, err := s.ListSubnet(ctx)
+	if err != nil {
+		return err
+	}
 	ch := s.MakeDeleteChannel(consts.SUBNET, len(subnets.Ss))
 
 	for _, subnet := range subnets.Ss {
 		tempSubnet := subnet
 		go func() {
-			ch <- s.DeleteSubnet(tempSubnet.Id)
+			ch <- s.DeleteSubnet(ctx, tempSubnet.Id)
 		}()
 	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("Subnets were deleted completely")
+	return nil
 }
 
 // security group
 
-func (s *Controller) CreateSecurityGroup(opts entity.CreateUpdateOptions) entity.Sg {
-	res := wrapper(constructSgRequestOpts)(opts, nil)
-	defer fasthttp.ReleaseResponse(res)
-
-	var sg entity.Sg
-	_ = json.Unmarshal(res.Body(), &sg)
-
-	log.Println("==============Create security group success", sg.Id)
-	return sg
+func (s *Controller) CreateSecurityGroup(ctx context.Context, opts *entity.CreateSecurityGroupOpts) (entity.Sg, error) {
+	sg, err := s.SecurityGroups().Create(ctx, opts)
+	return entity.Sg{SecurityGroup: sg}, err
 }
 
 
-func (s *Controller) GetSgsByName(sgName string) *entity.Sgs {
-	res := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		ParentID: "", Resource: consts.SECURITYGROUP,
-		ResourceLocation: strings.Replace(consts.SECURITYGROUPS, "_", "-", 1),
-		ResourceSuffix: fmt.Sprintf("name=%s", sgName)})
-	defer fasthttp.ReleaseResponse(res)
-
-	var sgs entity.Sgs
-	_ = json.Unmarshal(res.Body(), &sgs)
+func (s *Controller) GetSgsByName(ctx context.Context, sgName string) (*entity.Sgs, error) {
+	sgs, err := s.SecurityGroups().List(ctx, "name="+url.QueryEscape(sgName))
+	if err != nil {
+		return nil, err
+	}
 	log.Println("==============List sgs success", sgs)
-	return &sgs
+	return &entity.Sgs{Sgs: sgs, Count: len(sgs)}, nil
 }
 
-func (s *Controller) EnsureSgExist(sgName string) {
-	sgs := s.GetSgsByName(sgName)
-	if len(sgs.Sgs) == 0 {
-		sgId := CreateSecurityGroupHelper()
-		CreateSecurityRuleICMP(sgId)
-		CreateSecurityRuleSSH(sgId)
+func (s *Controller) EnsureSgExist(ctx context.Context, sgName string) error {
+	sgs, err := s.GetSgsByName(ctx, sgName)
+	if err != nil {
+		return err
+	}
+	if len(sgs.Sgs) != 0 {
+		return nil
+	}
+	sg, err := s.CreateSecurityGroup(ctx, &entity.CreateSecurityGroupOpts{Name: sgName})
+	if err != nil {
+		return err
+	}
+	for _, rule := range []*entity.CreateSecurityRuleOpts{
+		defaultICMPIngressSgRuleOpts(sg.Id), defaultICMPEgressSgRuleOpts(sg.Id),
+		defaultSSHIngressSgRuleOpts(sg.Id), defaultSSHEgressSgRuleOpts(sg.Id),
+	} {
+		if _, err = s.CreateSecurityRule(ctx, rule); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func (s *Controller) getSecurityGroup(sgId string) interface{} {
-	urlSuffix := fmt.Sprintf("security-groups/%s", sgId)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.SECURITYGROUP, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var sg entity.Sg
-	_ = json.Unmarshal(resp.Body(), &sg)
+func (s *Controller) getSecurityGroup(ctx context.Context, sgId string) (entity.Sg, error) {
+	sg, err := s.SecurityGroups().Get(ctx, sgId)
+	if err != nil {
+		return entity.Sg{}, err
+	}
 	log.Println(fmt.Sprintf("get sg==%+v", sg))
-	return sg
+	return entity.Sg{SecurityGroup: sg}, nil
 }
 
 
-func (s *Controller) listSecurityGroups() entity.Sgs {
-	urlSuffix := fmt.Sprintf("project_id=%s", s.projectID)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.SECURITYGROUP, ResourceLocation: consts.SECURITYGROUPS,
-		ResourceSuffix: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
+// projectQuery filters on the controller's project even for the admin, unlike
+// projectFilter, so the cleanup never reaches the default security groups
+// and router ports of other projects.
+func (s *Controller) projectQuery(ctx context.Context) (string, error) {
+	projectID, err := s.Project(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("project_id=%s", projectID), nil
+}
 
-	var sgs entity.Sgs
-	_ = json.Unmarshal(resp.Body(), &sgs)
-	log.Println("==============List sg success, there had", len(sgs.Sgs))
-	return sgs
+func (s *Controller) listSecurityGroups(ctx context.Context, opts ...ListOption) (entity.Sgs, error) {
+	query, err := s.projectQuery(ctx)
+	if err != nil {
+		return entity.Sgs{}, err
+	}
+	sgs, err := s.SecurityGroups().List(ctx, query, opts...)
+	if err != nil {
+		return entity.Sgs{}, err
+	}
+	log.Println("==============List sg success, there had", len(sgs))
+	return entity.Sgs{Sgs: sgs, Count: len(sgs)}, nil
 }
 
-func (s *Controller) deleteSecurityGroup(id string) Output {
+func (s *Controller) deleteSecurityGroup(ctx context.Context, id string) Output {
 	outputObj := Output{ParametersMap: map[string]string{"security_group_id": id}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-			outputObj.Response = err
-		}
-	}()
-
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		Resource: consts.SECURITYGROUP,
-		ResourceLocation: fmt.Sprintf("%s/%s", strings.Replace(consts.SECURITYGROUPS, "_", "-", 1), id)})
-	defer fasthttp.ReleaseResponse(resp)
-
-	outputObj.Response = resp.StatusCode()
-	if resp.StatusCode() == fasthttp.StatusOK {
-		outputObj.Success = true
-	}
-	return outputObj
+	return resultOutput(outputObj, s.SecurityGroups().Delete(ctx, id))
 }
 
-func (s *Controller) DeleteSecurityGroups() {
-	sgs := s.listSecurityGroups()
+func (s *Controller) DeleteSecurityGroups(ctx context.Context) error {
+	sgs, err := s.listSecurityGroups(ctx)
+	if err != nil {
+		return err
+	}
 	ch := s.MakeDeleteChannel(consts.SECURITYGROUP, len(sgs.Sgs))
 	for _, sg := range sgs.Sgs {
 		tempSg := sg
 		go func() {
-			ch <- s.deleteSecurityGroup(tempSg.Id)
+			ch <- s.deleteSecurityGroup(ctx, tempSg.Id)
 		}()
 	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("Security groups were deleted completely")
+	return nil
 }
 
 
 // security group rule
 
-func (s *Controller) listSecurityGroupRules() entity.SgRules {
-	urlSuffix := fmt.Sprintf("project_id=%s", s.projectID)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.SECURITYGROUPRULE, ResourceLocation: consts.SECURITYGROUPRULES,
-		ResourceSuffix: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var sgRules entity.SgRules
-	_ = json.Unmarshal(resp.Body(), &sgRules)
-
-	log.Println("==============List sg rule success, there had", len(sgRules.Srs))
-	return sgRules
+func (s *Controller) listSecurityGroupRules(ctx context.Context, opts ...ListOption) (entity.SgRules, error) {
+	query, err := s.projectQuery(ctx)
+	if err != nil {
+		return entity.SgRules{}, err
+	}
+	sgRules, err := s.SecurityGroupRules().List(ctx, query, opts...)
+	if err != nil {
+		return entity.SgRules{}, err
+	}
+	log.Println("==============List sg rule success, there had", len(sgRules))
+	return entity.SgRules{Srs: sgRules}, nil
 }
 
-func (s *Controller) deleteSecurityGroupRule(id string) Output {
-	outputObj := Output{ParametersMap: map[string]string{"security_group_id": id}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-			outputObj.Response = err
-		}
-	}()
-
-	urlSuffix := fmt.Sprintf("security-group-rules/%s", id)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.SECURITYGROUPRULE, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	outputObj.Response = resp.StatusCode()
-	if resp.StatusCode() == fasthttp.StatusOK {
-		outputObj.Success = true
-	}
-	return outputObj
+func (s *Controller) deleteSecurityGroupRule(ctx context.Context, id string) Output {
+	outputObj := Output{ParametersMap: map[string]string{"security_group_rule_id": id}}
+	return resultOutput(outputObj, s.SecurityGroupRules().Delete(ctx, id))
 }
 
-func (s *Controller) DeleteSecurityGroupRules() {
-	sgRules := s.listSecurityGroupRules()
+func (s *Controller) DeleteSecurityGroupRules(ctx context.Context) error {
+	sgRules, err := s.listSecurityGroupRules(ctx)
+	if err != nil {
+		return err
+	}
 	ch := s.MakeDeleteChannel(consts.SECURITYGROUPRULE, len(sgRules.Srs))
 	for _, sgRule := range sgRules.Srs {
 		tempSgRule := sgRule
 		go func() {
-			ch <- s.deleteSecurityGroupRule(tempSgRule.Id)
+			ch <- s.deleteSecurityGroupRule(ctx, tempSgRule.Id)
 		}()
 	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("Security group rules were deleted completely")
+	return nil
 }
 
-func (s *Controller) CreateSecurityRule(opts entity.CreateUpdateOptions) {
-	res := wrapper(constructSgRuleRequestOpts)(opts, nil)
-	defer fasthttp.ReleaseResponse(res)
-
-	var sgRule entity.SgRule
-	_ = json.Unmarshal(res.Body(), &sgRule)
-
-	log.Println("==============Create security group rule success", sgRule.Id)
+func (s *Controller) CreateSecurityRule(ctx context.Context, opts *entity.CreateSecurityRuleOpts) (entity.SgRule, error) {
+	sgRule, err := s.SecurityGroupRules().Create(ctx, opts)
+	return entity.SgRule{SecurityGroupRule: sgRule}, err
 }
 
 // router
 
-func (s *Controller) CreateRouter(opts entity.CreateUpdateOptions) entity.RouterMap {
-	res := wrapper(constructRouterRequestOpts)(opts, nil)
-	defer fasthttp.ReleaseResponse(res)
-
-	var router entity.RouterMap
-	_ = json.Unmarshal(res.Body(), &router)
-
-	log.Println("==============Create router success", router.Id)
-	return router
+func (s *Controller) CreateRouter(ctx context.Context, opts *entity.CreateRouterOpts) (entity.RouterMap, error) {
+	router, err := s.Routers().Create(ctx, opts)
+	return entity.RouterMap{Router: router}, err
 }
 
-func (s *Controller) SetRouterGateway(opts entity.CreateUpdateOptions, routerId string) entity.RouterMap {
-	res := wrapper(constructSetRouterGatewayRequestOpts)(opts, &ExtraOption{ParentID: routerId})
-	defer fasthttp.ReleaseResponse(res)
-
-	var router entity.RouterMap
-	_ = json.Unmarshal(res.Body(), &router)
-
-	log.Println("==============Set router gateway success", router.Id)
-	return router
+func (s *Controller) SetRouterGateway(ctx context.Context, opts *entity.UpdateRouterOpts, routerId string) (entity.RouterMap, error) {
+	router, err := s.Routers().Update(ctx, routerId, opts)
+	if err == nil {
+		log.Println("==============Set router gateway success", routerId)
+	}
+	return entity.RouterMap{Router: router}, err
 }
 
-func (s *Controller) AddRouterInterface(opts entity.CreateUpdateOptions) {
-	res := wrapper(constructRouterInterfaceRequestOpts)(opts, nil)
-	defer fasthttp.ReleaseResponse(res)
-
+func (s *Controller) AddRouterInterface(ctx context.Context, opts *entity.AddRouterInterfaceOpts) (entity.RouterInterface, error) {
 	var routerInterface entity.RouterInterface
-	_ = json.Unmarshal(res.Body(), &routerInterface)
-
-	log.Println("==============Add router interface success")
+	err := s.Routers().call(ctx, UPDATE, opts.RouterId, "add_router_interface", opts, &routerInterface)
+	if err == nil {
+		log.Println("==============Add router interface success")
+	}
+	return routerInterface, err
 }
 
-func (s *Controller) RemoveRouterInterface(routerId, subnetId string) Output {
+func (s *Controller) RemoveRouterInterface(ctx context.Context, routerId, subnetId string) Output {
 	outputObj := Output{ParametersMap: map[string]string{"router_id": routerId, "subnetId": subnetId}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-			outputObj.Response = err
-			log.Println("==============Remove router interface failed")
-		} else {
-			log.Println("==============Remove router interface success")
-		}
-	}()
-	urlSuffix := fmt.Sprintf("routers/%s/remove_router_interface", routerId)
-	resp := wrapper(constructRouterInterfaceRequestOpts)(
-		defaultRouterInterfaceOpts(routerId, subnetId), &ExtraOption{
-			Resource: consts.ROUTER, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	outputObj.Response = resp.Body()
-	outputObj.Success = true
+	err := s.Routers().call(ctx, UPDATE, routerId, "remove_router_interface",
+		defaultRouterInterfaceOpts(routerId, subnetId), nil)
+	outputObj = resultOutput(outputObj, err)
+	if outputObj.Success {
+		log.Println("==============Remove router interface success")
+	}
 	return outputObj
 }
 
-func (s *Controller) DeleteRouterInterfaces() {
-	interfacePorts := s.listRouterInterfacePorts()
-	ch := s.MakeDeleteChannel(consts.ROUTERINTERFACE, len(interfacePorts.Ps))
+func (s *Controller) DeleteRouterInterfaces(ctx context.Context) error {
+	interfacePorts, err := s.listRouterInterfacePorts(ctx)
+	if err != nil {
+		return err
+	}
+	var length int
+	for _, port := range interfacePorts.Ps {
+		length += len(port.FixedIps)
+	}
+	ch := s.MakeDeleteChannel(consts.ROUTERINTERFACE, length)
 
 	for _, port := range interfacePorts.Ps {
 		routerId := port.DeviceId
@@ -567,67 +764,55 @@ func (s *Controller) DeleteRouterInterfaces() {
 		for _, fixedIp := range fixedIps {
 			tempFixedIp := fixedIp
 			go func() {
-				ch <- s.RemoveRouterInterface(routerId, tempFixedIp.SubnetId)
+				ch <- s.RemoveRouterInterface(ctx, routerId, tempFixedIp.SubnetId)
 			}()
 		}
 	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("Router interfaces were deleted completely")
+	return nil
 }
 
-func (s *Controller) ListRouters() entity.Routers {
-	var urlSuffix = ""
-	if s.projectName != consts.ADMIN {
-		urlSuffix = fmt.Sprintf("project_id=%s", s.projectID)
+func (s *Controller) ListRouters(ctx context.Context, opts ...ListOption) (entity.Routers, error) {
+	query, err := s.projectFilter(ctx)
+	if err != nil {
+		return entity.Routers{}, err
 	}
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.ROUTER, ResourceLocation: consts.ROUTERS, ResourceSuffix: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var routers entity.Routers
-	_ = json.Unmarshal(resp.Body(), &routers)
-	log.Println("==============List routers success, there had", routers.Count)
-	return routers
+	routers, err := s.Routers().List(ctx, query, opts...)
+	if err != nil {
+		return entity.Routers{}, err
+	}
+	log.Println("==============List routers success, there had", len(routers))
+	return entity.Routers{Rs: routers, Count: len(routers)}, nil
 }
 
-func (s *Controller) listRouterInterfacePorts() entity.Ports {
-	urlSuffix := fmt.Sprintf("device_owner=network:router_interface&project_id=%s", s.projectID)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.ROUTERINTERFACE, ResourceLocation: consts.PORTS,
-		ResourceSuffix: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var ports entity.Ports
-	_ = json.Unmarshal(resp.Body(), &ports)
-	log.Println("==============List router interface port success, there had", ports.Count)
-	return ports
+func (s *Controller) listRouterInterfacePorts(ctx context.Context, opts ...ListOption) (entity.Ports, error) {
+	query, err := s.projectQuery(ctx)
+	if err != nil {
+		return entity.Ports{}, err
+	}
+	ports, err := s.Ports().List(ctx, joinQuery("device_owner="+consts.NETWORKROUTERINTERFACE, query), opts...)
+	if err != nil {
+		return entity.Ports{}, err
+	}
+	log.Println("==============List router interface port success, there had", len(ports))
+	return entity.Ports{Ps: ports, Count: len(ports)}, nil
 }
 
 
-func (s *Controller) updateRouterNoRoutes(id string) Output {
+func (s *Controller) updateRouterNoRoutes(ctx context.Context, id string) Output {
 	outputObj := Output{ParametersMap: map[string]string{"router_id": id}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-			outputObj.Response = err
-		}
-	}()
-
-	opts := &entity.UpdateRouterOpts{Routes: new([]entity.Route)}
-	resp := wrapper(constructRouterRequestOpts)(opts, &ExtraOption{
-		Resource: consts.ROUTER, ResourceLocation: fmt.Sprintf("%s/%s", consts.ROUTERS, id)})
-	defer fasthttp.ReleaseResponse(resp)
-
-	outputObj.Response = ""
-	outputObj.Success = true
-	return outputObj
+	_, err := s.Routers().Update(ctx, id, &entity.UpdateRouterOpts{Routes: new([]entity.Route)})
+	return resultOutput(outputObj, err)
 }
 
-func (s *Controller) DeleteRouterRoutes() {
-	routers := s.ListRouters()
+func (s *Controller) DeleteRouterRoutes(ctx context.Context) error {
+	routers, err := s.ListRouters(ctx)
+	if err != nil {
+		return err
+	}
 	length := 0
 	for _, router := range routers.Rs {
 		if len(router.Routes) != 0 {
@@ -639,560 +824,468 @@ func (s *Controller) DeleteRouterRoutes() {
 
 	for _, router := range routers.Rs {
 		if len(router.Routes) != 0 {
+			tempRouter := router
 			go func() {
-				ch <- s.updateRouterNoRoutes(router.Id)
+				ch <- s.updateRouterNoRoutes(ctx, tempRouter.Id)
 			}()
 		}
 	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("Router routes were deleted completely")
+	return nil
 }
 
-func (s *Controller) DeleteRouter(routerId string) Output {
+func (s *Controller) DeleteRouter(ctx context.Context, routerId string) Output {
 	outputObj := Output{ParametersMap: map[string]string{"router_id": routerId}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-			outputObj.Response = err
-			log.Println("==============Delete router failed", routerId)
-		} else {
-			log.Println("==============Delete router success", routerId)
-		}
-	}()
-
-	urlSuffix := fmt.Sprintf("%s/%s", consts.ROUTERS, routerId)
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		Resource: consts.ROUTER, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	outputObj.Response = resp.StatusCode()
-	if resp.StatusCode() == fasthttp.StatusOK {
-		outputObj.Success = true
-	}
-	return outputObj
+	return resultOutput(outputObj, s.Routers().Delete(ctx, routerId))
 }
 
-func (s *Controller) DeleteRouters() {
-	routers := s.ListRouters()
+func (s *Controller) DeleteRouters(ctx context.Context) error {
+	routers, err := s.ListRouters(ctx)
+	if err != nil {
+		return err
+	}
 	ch := s.MakeDeleteChannel(consts.ROUTER, len(routers.Rs))
 	for _, router := range routers.Rs {
 		tempRouter := router
 		go func() {
-			ch <- s.DeleteRouter(tempRouter.Id)
+			ch <- s.DeleteRouter(ctx, tempRouter.Id)
 		}()
 	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("Routers were deleted completely")
+	return nil
 }
 
-func (s *Controller) ClearRouterGateway(routerId, extNetId string) Output {
+func (s *Controller) ClearRouterGateway(ctx context.Context, routerId, extNetId string) Output {
 	outputObj := Output{ParametersMap: map[string]string{"router_id": routerId, "ext_net_id": extNetId}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-			outputObj.Response = err
-		}
-	}()
-	urlSuffix := fmt.Sprintf("routers/%s", routerId)
-	opts := &entity.UpdateRouterOpts{
-		GatewayInfo: &entity.GatewayInfo{}}
-
-	resp := wrapper(constructRouterRequestOpts)(opts, &ExtraOption{
-		ParentID: routerId, Resource: consts.ROUTER, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	outputObj.Success = true
-	outputObj.Response = ""
-	log.Println("==============Clear router gateway success, router", routerId)
+	_, err := s.Routers().Update(ctx, routerId, &entity.UpdateRouterOpts{
+		GatewayInfo: &entity.GatewayInfo{}})
+	outputObj = resultOutput(outputObj, err)
+	if outputObj.Success {
+		log.Println("==============Clear router gateway success, router", routerId)
+	}
 	return outputObj
 }
 
-func (s *Controller) DeleteRouterGateways() {
-	routers := s.ListRouters()
+func (s *Controller) DeleteRouterGateways(ctx context.Context) error {
+	routers, err := s.ListRouters(ctx)
+	if err != nil {
+		return err
+	}
 	var length int
 	for _, router := range routers.Rs {
-		if !reflect.DeepEqual(router.GatewayInfo, nil) {
+		if router.GatewayInfo.NetworkID != "" {
 			length++
 		}
 	}
 	ch := s.MakeDeleteChannel(consts.ROUTERGATEWAY, length)
 	for _, router := range routers.Rs {
-		if !reflect.DeepEqual(router.GatewayInfo, nil) {
+		if router.GatewayInfo.NetworkID != "" {
+			tempRouter := router
 			go func() {
-				ch <- s.ClearRouterGateway(router.Id, router.GatewayInfo.NetworkID)
+				ch <- s.ClearRouterGateway(ctx, tempRouter.Id, tempRouter.GatewayInfo.NetworkID)
 			}()
 		}
 	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("Router gateways were deleted completely")
+	return nil
 }
 
-func (s *Controller) GetRouter(routerId string) entity.RouterMap {
-	urlSuffix := fmt.Sprintf("routers/%s", routerId)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.ROUTER, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var router entity.RouterMap
-	_ = json.Unmarshal(resp.Body(), &router)
+func (s *Controller) GetRouter(ctx context.Context, routerId string) (entity.RouterMap, error) {
+	router, err := s.Routers().Get(ctx, routerId)
+	if err != nil {
+		return entity.RouterMap{}, err
+	}
 	log.Println("==============Get router success", routerId)
-	return router
+	return entity.RouterMap{Router: router}, nil
 }
 
 // server
 
-func (s *Controller) makeSureInstanceActive(instanceId string) {
-	instance := s.GetInstanceDetail(instanceId)
-	if instance == nil {
-		time.Sleep(2 * time.Second)
-		for instance == nil {
-			instance = s.GetInstanceDetail(instanceId)
-			time.Sleep(2 * time.Second)
+func (s *Controller) makeSureInstanceActive(ctx context.Context, instanceId string) error {
+	err := waitFor(ctx, "instance "+instanceId+" to become ACTIVE", 10*time.Second, consts.Timeout, func() (bool, error) {
+		instance, err := s.GetInstanceDetail(ctx, instanceId)
+		if err != nil {
+			return false, err
 		}
-	}
-	timeout := 2 * 60 * time.Second
-	done := make(chan bool, 1)
-	go func() {
-		state := instance.Server.Status
-		for state != "ACTIVE" {
-			time.Sleep(10 * time.Second)
-			instance = s.GetInstanceDetail(instanceId)
-			state =instance.Server.Status
+		if instance.Server.Status == "ERROR" {
+			return false, fmt.Errorf("instance %s went to ERROR", instanceId)
 		}
-		done <- true
-	}()
-	select {
-	case <-done:
-		log.Println("*******************Create instance success")
-	case <-time.After(timeout):
-		log.Println("*******************Create instance timeout")
+		return instance.Server.Status == consts.ACTIVE, nil
+	})
+	if err != nil {
+		log.Println("*******************Create instance failed", err)
+		return err
 	}
+	log.Println("*******************Create instance success")
+	return nil
 }
 
-func (s *Controller) CreateInstance(opts entity.CreateUpdateOptions) entity.ServerMap {
-	res := wrapper(constructInstanceRequestOpts)(opts, nil)
-	defer fasthttp.ReleaseResponse(res)
+// CreateInstance creates a server and waits for it to be ACTIVE, the flavor
+// and server group of CreateInstanceOpts may be given by name.
+func (s *Controller) CreateInstance(ctx context.Context, opts *entity.CreateInstanceOpts) (entity.ServerMap, error) {
+	if err := s.resolveInstanceOpts(ctx, opts); err != nil {
+		return entity.ServerMap{}, err
+	}
+	server, err := s.Servers().Create(ctx, opts)
+	if err != nil {
+		return entity.ServerMap{Server: server}, err
+	}
+	return entity.ServerMap{Server: server}, s.makeSureInstanceActive(ctx, server.Id)
+}
 
-	var server entity.ServerMap
-	_ = json.Unmarshal(res.Body(), &server)
-	s.makeSureInstanceActive(server.Id)
-	return server
+// resolveInstanceOpts turns the flavor and server group names of opts into
+// the ids nova expects.
+func (s *Controller) resolveInstanceOpts(ctx context.Context, opts *entity.CreateInstanceOpts) error {
+	if opts.FlavorRef == "" && opts.FlavorName != "" {
+		flavorId, err := s.flavorIdByName(ctx, opts.FlavorName)
+		if err != nil {
+			return err
+		}
+		opts.FlavorRef = flavorId
+	}
+	if opts.ServerGroup != "" {
+		group, err := s.FindServerGroup(ctx, opts.ServerGroup)
+		if err != nil {
+			return err
+		}
+		if opts.SchedulerHints == nil {
+			opts.SchedulerHints = make(map[string]interface{})
+		}
+		opts.SchedulerHints["group"] = group.Id
+	}
+	return nil
 }
 
-func (s *Controller) GetInstanceDetail(instanceId string) *entity.ServerMap {
-	res := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		ParentID: "", Resource: consts.SERVER,
-		ResourceLocation: fmt.Sprintf("%s/%s", consts.SERVERS, instanceId),
-		ResourceSuffix: ""})
-	defer fasthttp.ReleaseResponse(res)
+func (s *Controller) GetInstanceDetail(ctx context.Context, instanceId string) (*entity.ServerMap, error) {
+	server, err := s.Servers().Get(ctx, instanceId)
+	if err != nil {
+		return nil, err
+	}
+	log.Println("==============Get server success", instanceId, server.Status)
+	return &entity.ServerMap{Server: server}, nil
+}
 
-	var server entity.ServerMap
-	_ = json.Unmarshal(res.Body(), &server)
-	log.Println("==============List server success", string(res.Body()))
-	return &server
+func (s *Controller) serversPager(opts []ListOption) *pager {
+	return s.newPager(&ExtraOption{
+		Resource: consts.SERVER, ResourceLocation: fmt.Sprintf("%s/detail", consts.SERVERS)},
+		consts.SERVERS, MarkerPaging, opts)
 }
 
-// port
+// ListServers lists the servers of the project the token is scoped to.
+func (s *Controller) ListServers(ctx context.Context, opts ...ListOption) (entity.Servers, error) {
+	var servers entity.Servers
+	if err := s.listAll(ctx, s.serversPager(opts), &servers); err != nil {
+		return servers, err
+	}
+	log.Println("==============List servers success, there had", servers.Count)
+	return servers, nil
+}
 
-func (s *Controller) CreatePort(opts entity.CreateUpdateOptions) entity.PortMap {
-	res := wrapper(constructPortRequestOpts)(opts, nil)
-	defer fasthttp.ReleaseResponse(res)
+// IterServers streams the servers page by page.
+func (s *Controller) IterServers(ctx context.Context, opts ...ListOption) *Iterator[entity.Servers] {
+	return newIterator[entity.Servers](s.serversPager(opts), nil)
+}
 
-	var port entity.PortMap
-	_ = json.Unmarshal(res.Body(), &port)
+// port
 
-	log.Println("==============Create internal port success", port.Id)
-	return port
+func (s *Controller) CreatePort(ctx context.Context, opts *entity.CreatePortOpts) (entity.PortMap, error) {
+	port, err := s.Ports().Create(ctx, opts)
+	return entity.PortMap{Port: port}, err
 }
 
-func (s *Controller) GetPort(portId string) entity.PortMap {
-	urlSuffix := fmt.Sprintf("ports/%s", portId)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.PORT, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var port entity.PortMap
-	_ = json.Unmarshal(resp.Body(), &port)
+func (s *Controller) GetPort(ctx context.Context, portId string) (entity.PortMap, error) {
+	port, err := s.Ports().Get(ctx, portId)
+	if err != nil {
+		return entity.PortMap{}, err
+	}
 	log.Println("==============Get port success", portId)
-	return port
+	return entity.PortMap{Port: port}, nil
 }
 
-func (s *Controller) GetPortIP(portId string) string {
-	port := s.GetPort(portId)
-	return port.FixedIps[0].IpAddress
+func (s *Controller) GetPortIP(ctx context.Context, portId string) (string, error) {
+	port, err := s.GetPort(ctx, portId)
+	if err != nil {
+		return "", err
+	}
+	if len(port.FixedIps) == 0 {
+		return "", fmt.Errorf("port %s has no fixed ip", portId)
+	}
+	return port.FixedIps[0].IpAddress, nil
 }
 
-func (s *Controller) ListPort() entity.Ports {
-	var urlSuffix = ""
-	if s.projectName != consts.ADMIN {
-		urlSuffix = fmt.Sprintf("project_id=%s", s.projectID)
+func (s *Controller) ListPort(ctx context.Context, opts ...ListOption) (entity.Ports, error) {
+	query, err := s.projectFilter(ctx)
+	if err != nil {
+		return entity.Ports{}, err
+	}
+	ports, err := s.Ports().List(ctx, query, opts...)
+	if err != nil {
+		return entity.Ports{}, err
 	}
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.PORT, ResourceLocation: consts.PORTS,
-		ResourceSuffix: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var ports entity.Ports
-	_ = json.Unmarshal(resp.Body(), &ports)
 	log.Println("==============List port success")
-	return ports
+	return entity.Ports{Ps: ports, Count: len(ports)}, nil
 }
 
-func (s *Controller) DeletePort(ipId string) Output {
-	outputObj := Output{ParametersMap: map[string]string{"port_id": ipId}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-			outputObj.Response = err
-		}
-	}()
-
-	urlSuffix := fmt.Sprintf("%s/%s", consts.PORTS, ipId)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.NETWORK, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
+// IterPorts streams the ports page by page.
+func (s *Controller) IterPorts(ctx context.Context, opts ...ListOption) *Iterator[entity.Ports] {
+	return newIterator[entity.Ports](s.Ports().projectPager(ctx, opts))
+}
 
-	outputObj.Response = resp.StatusCode()
-	if resp.StatusCode() == fasthttp.StatusOK {
-		outputObj.Success = true
-	}
-	return outputObj
+func (s *Controller) DeletePort(ctx context.Context, ipId string) Output {
+	outputObj := Output{ParametersMap: map[string]string{"port_id": ipId}}
+	return resultOutput(outputObj, s.Ports().Delete(ctx, ipId))
 }
 
-func (s *Controller) DeletePorts() {
-	ports := s.ListPort()
+func (s *Controller) DeletePorts(ctx context.Context) error {
+	ports, err := s.ListPort(ctx)
+	if err != nil {
+		return err
+	}
 	ch := s.MakeDeleteChannel(consts.PORT, len(ports.Ps))
 
 	for _, port := range ports.Ps {
 		tempPort := port
 		go func() {
-			ch <- s.DeletePort(tempPort.Id)
+			ch <- s.DeletePort(ctx, tempPort.Id)
 		}()
 	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("Ports were deleted completely")
+	return nil
 }
 
 
 // floating ip
 
-func (s *Controller) GetFIP(fipId string) entity.FipMap {
-	urlSuffix := fmt.Sprintf("floatingips/%s", fipId)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.FLOATINGIP, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var fip entity.FipMap
-	_ = json.Unmarshal(resp.Body(), &fip)
+func (s *Controller) GetFIP(ctx context.Context, fipId string) (entity.FipMap, error) {
+	fip, err := s.FloatingIPs().Get(ctx, fipId)
+	if err != nil {
+		return entity.FipMap{}, err
+	}
 	log.Println("==============Get fip success", fipId)
-	return fip
+	return entity.FipMap{Floatingip: fip}, nil
 }
 
-func (s *Controller) ListFIPs() entity.Fips {
-	var urlSuffix = ""
-	if s.projectName != consts.ADMIN {
-		urlSuffix = fmt.Sprintf("project_id=%s", s.projectID)
+func (s *Controller) ListFIPs(ctx context.Context, opts ...ListOption) (entity.Fips, error) {
+	query, err := s.projectFilter(ctx)
+	if err != nil {
+		return entity.Fips{}, err
 	}
-
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.FLOATINGIP, ResourceLocation: consts.FLOATINGIPS,
-		ResourceSuffix: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var fs entity.Fips
-	_ = json.Unmarshal(resp.Body(), &fs)
-	log.Println("==============List fip success, there had", fs.Count)
-	return fs
+	fips, err := s.FloatingIPs().List(ctx, query, opts...)
+	if err != nil {
+		return entity.Fips{}, err
+	}
+	log.Println("==============List fip success, there had", len(fips))
+	return entity.Fips{Fs: fips, Count: len(fips)}, nil
 }
 
-func (s *Controller) DeleteFIP(fipId string) Output {
+func (s *Controller) DeleteFIP(ctx context.Context, fipId string) Output {
 	outputObj := Output{ParametersMap: map[string]string{"floatingip_id": fipId}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-			outputObj.Response = err
-		}
-	}()
-
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		Resource: consts.FLOATINGIP,
-		ResourceLocation: fmt.Sprintf("%s/%s", consts.FLOATINGIPS, fipId)})
-	defer fasthttp.ReleaseResponse(resp)
-
-	outputObj.Response = resp.StatusCode()
-	if resp.StatusCode() == fasthttp.StatusOK {
-		outputObj.Success = true
-	}
-	return outputObj
+	return resultOutput(outputObj, s.FloatingIPs().Delete(ctx, fipId))
 }
 
-func (s *Controller) DeleteFloatingips() {
-	fips := s.ListFIPs()
+func (s *Controller) DeleteFloatingips(ctx context.Context) error {
+	fips, err := s.ListFIPs(ctx)
+	if err != nil {
+		return err
+	}
 	ch := s.MakeDeleteChannel(consts.FLOATINGIP, len(fips.Fs))
 
 	for _, fip := range fips.Fs {
 		tempFip := fip
 		go func() {
-			ch <- s.DeleteFIP(tempFip.Id)
+			ch <- s.DeleteFIP(ctx, tempFip.Id)
 		}()
 	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("Floatingips were deleted completely")
+	return nil
 }
 
 // port forwarding
 
-func (s *Controller) GetPortForwarding(fipId string, pfId string) entity.PortForwardingMap {
-	urlSuffix := fmt.Sprintf("%s/%s/%s/%s", consts.FLOATINGIPS, fipId, consts.PORTFORWARDINGS, pfId)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.PORTFORWARDING, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var pf entity.PortForwardingMap
-	_ = json.Unmarshal(resp.Body(), &pf)
-
+func (s *Controller) GetPortForwarding(ctx context.Context, fipId string, pfId string) (entity.PortForwardingMap, error) {
+	pf, err := s.PortForwardings(fipId).Get(ctx, pfId)
+	if err != nil {
+		return entity.PortForwardingMap{}, err
+	}
 	log.Printf("==============Get port forwarding success %+v\n", pf)
-	return pf
+	return entity.PortForwardingMap{PortForwarding: pf}, nil
 }
 
-func (s *Controller) ListPortForwarding(fipId string) entity.PortForwardings {
-	urlSuffix := fmt.Sprintf("%s/%s/%s", consts.FLOATINGIPS, fipId, consts.PORTFORWARDINGS)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.PORTFORWARDING, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var pfs entity.PortForwardings
-	_ = json.Unmarshal(resp.Body(), &pfs)
-
+func (s *Controller) ListPortForwarding(ctx context.Context, fipId string, opts ...ListOption) (entity.PortForwardings, error) {
+	pfs, err := s.PortForwardings(fipId).List(ctx, "", opts...)
+	if err != nil {
+		return entity.PortForwardings{}, err
+	}
 	log.Printf("==============List port forwarding success %+v\n", pfs)
-	return pfs
+	return entity.PortForwardings{Pfs: pfs}, nil
 }
 
-func (s *Controller) DeletePortForwarding(fipId string, pfId string) Output {
+func (s *Controller) DeletePortForwarding(ctx context.Context, fipId string, pfId string) Output {
 	outputObj := Output{ParametersMap: map[string]string{"floatingip_id": fipId, "port_forwarding_id": pfId}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-			outputObj.Response = err
-		}
-	}()
-	urlSuffix := fmt.Sprintf("%s/%s/%s/%s", consts.FLOATINGIPS, fipId, consts.PORTFORWARDINGS, pfId)
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		Resource: consts.PORTFORWARDING, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	outputObj.Response = resp.StatusCode()
-	if resp.StatusCode() == fasthttp.StatusOK {
-		outputObj.Success = true
-	}
-	return outputObj
+	return resultOutput(outputObj, s.PortForwardings(fipId).Delete(ctx, pfId))
 }
 
-func (s *Controller) DeletePortForwardings() {
-	fips := s.ListFIPs()
+func (s *Controller) DeletePortForwardings(ctx context.Context) error {
+	fips, err := s.ListFIPs(ctx)
+	if err != nil {
+		return err
+	}
 	var pfsMap = make(map[string]entity.PortForwardings)
 	var length int
 	for _, fip := range fips.Fs {
-		tmpPfs := s.ListPortForwarding(fip.Id)
+		tmpPfs, err := s.ListPortForwarding(ctx, fip.Id)
+		if err != nil {
+			return err
+		}
 		pfsMap[fip.Id] = tmpPfs
 		length += len(tmpPfs.Pfs)
 	}
 
 	ch := s.MakeDeleteChannel(consts.PORTFORWARDING, length)
 	for fipId, pfs := range pfsMap {
+		tmpFipId := fipId
 		for _, pf := range pfs.Pfs {
 			tmpPf := pf
 			go func() {
-				ch <- s.DeletePortForwarding(fipId, tmpPf.Id)
+				ch <- s.DeletePortForwarding(ctx, tmpFipId, tmpPf.Id)
 			}()
 		}
 	}
 
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("Port forwarding were deleted completely")
+	return nil
 }
 
 // qos policy
 
-func (s *Controller) createQosPolicy(opts entity.CreateUpdateOptions) entity.QosPolicyMap {
-	res := wrapper(constructSgRuleRequestOpts)(opts, &ExtraOption{
-		ResourceLocation: "qos/policies"})
-	defer fasthttp.ReleaseResponse(res)
-
-	var qosPolicy entity.QosPolicyMap
-	_ = json.Unmarshal(res.Body(), &qosPolicy)
-	log.Println("==============Create qos policy success", qosPolicy.Id)
-	return qosPolicy
+func (s *Controller) createQosPolicy(ctx context.Context, opts *entity.CreateQosPolicyOpts) (entity.QosPolicyMap, error) {
+	policy, err := s.QosPolicies().Create(ctx, opts)
+	return entity.QosPolicyMap{Policy: policy}, err
 }
 
-func (s *Controller) CreateBandwidthLimitRule(opts entity.CreateUpdateOptions, qosPolicyId string) entity.BandwidthLimitRuleMap {
-	res := wrapper(constructBandwidthLimitRuleRequestOpts)(opts, &ExtraOption{ParentID: qosPolicyId})
-	defer fasthttp.ReleaseResponse(res)
-
+func (s *Controller) CreateBandwidthLimitRule(ctx context.Context, opts *entity.CreateBandwidthLimitRuleOpts, qosPolicyId string) (entity.BandwidthLimitRuleMap, error) {
 	var rule entity.BandwidthLimitRuleMap
-	_ = json.Unmarshal(res.Body(), &rule)
-
-	log.Println("==============Create bandwidth_limit_rule success")
-	return rule
-}
-
-func (s *Controller) DeleteBandwidthLimitRules() {
-	qoss := s.listQoss()
-	var length int
-	for _, qos := range qoss.Qps {
-		rules := qos.Rules
-		for _, rule := range rules {
-			if rule.Type == "bandwidth_limit" {
-				length++
-			}
-		}
+	err := s.QosPolicies().call(ctx, CREATE, qosPolicyId, consts.BANDWIDTH_LIMIT_RULES, opts, &rule)
+	if err == nil {
+		log.Println("==============Create bandwidth_limit_rule success")
 	}
-	ch := s.MakeDeleteChannel(consts.BANDWIDTH_LIMIT_RULE, length)
-	for _, qos := range qoss.Qps {
-		rules := qos.Rules
-		for _, rule := range rules {
-			if rule.Type == "bandwidth_limit" {
-				go func() {
-					ch <- s.DeleteQosRule(rule.Type, qos.Id, rule.Id)
-				}()
-			}
-		}
-	}
-
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
-	}
-	log.Println("Bandwidth limit rules were deleted completely")
+	return rule, err
 }
 
-
-func (s *Controller) DeleteDscpMarkingRules() {
-	qoss := s.listQoss()
-	var length int
-	for _, qos := range qoss.Qps {
-		rules := qos.Rules
-		for _, rule := range rules {
-			if rule.Type == "dscp_marking" {
-				length++
-			}
-		}
+// deleteQosRules removes every rule of ruleType from the project's qos policies.
+func (s *Controller) deleteQosRules(ctx context.Context, resourceType, ruleType string) error {
+	qoss, err := s.listQoss(ctx)
+	if err != nil {
+		return err
 	}
-	ch := s.MakeDeleteChannel(consts.DSCP_MARKING_RULE, length)
-	for _, qos := range qoss.Qps {
-		rules := qos.Rules
-		for _, rule := range rules {
-			if rule.Type == "dscp_marking" {
-				go func() {
-					ch <- s.DeleteQosRule(rule.Type, qos.Id, rule.Id)
-				}()
-			}
-		}
-	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
-	}
-	log.Println("Dscp marking rules were deleted completely")
-}
-
-func (s *Controller) DeleteMinimumBandwidthRules() {
-	qoses := s.listQoss()
 	var length int
-	for _, qos := range qoses.Qps {
-		rules := qos.Rules
-		for _, rule := range rules {
-			if rule.Type == "minimum_bandwidth" {
+	for _, qos := range qoss.Qps {
+		for _, rule := range qos.Rules {
+			if rule.Type == ruleType {
 				length++
 			}
 		}
 	}
-	ch := s.MakeDeleteChannel(consts.MINIMUM_BANDWIDTH_RULE, length)
-	for _, qos := range qoses.Qps {
-		rules := qos.Rules
-		for _, rule := range rules {
-			if rule.Type == "minimum_bandwidth" {
+	ch := s.MakeDeleteChannel(resourceType, length)
+	for _, qos := range qoss.Qps {
+		qosId := qos.Id
+		for _, rule := range qos.Rules {
+			if rule.Type == ruleType {
+				tempRule := rule
 				go func() {
-					ch <- s.DeleteQosRule(rule.Type, qos.Id, rule.Id)
+					ch <- s.DeleteQosRule(ctx, tempRule.Type, qosId, tempRule.Id)
 				}()
 			}
 		}
 	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
-	log.Println("Minimum bandwidth rules were deleted completely")
+	return nil
 }
 
-func (s *Controller) listQoss() entity.QosPolicies {
-	var urlSuffix = ""
-	if s.projectName != consts.ADMIN {
-		urlSuffix = fmt.Sprintf("project_id=%s", s.projectID)
+func (s *Controller) DeleteBandwidthLimitRules(ctx context.Context) error {
+	if err := s.deleteQosRules(ctx, consts.BANDWIDTH_LIMIT_RULE, "bandwidth_limit"); err != nil {
+		return err
 	}
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.QOS_POLICY, ResourceLocation: consts.QOS_POLICIES,
-		ResourceSuffix: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var qos entity.QosPolicies
-	_ = json.Unmarshal(resp.Body(), &qos)
-	log.Println("==============List qos policy success, there had", qos.Count)
-	return qos
+	log.Println("Bandwidth limit rules were deleted completely")
+	return nil
 }
 
-func (s *Controller) DeleteQos(qosId string) Output {
-	outputObj := Output{ParametersMap: map[string]string{"qos_policy_id": qosId}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-			outputObj.Response = err
-		}
-	}()
 
-	urlSuffix := fmt.Sprintf("qos/policies/%s", qosId)
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		Resource: consts.NETWORK, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
+func (s *Controller) DeleteDscpMarkingRules(ctx context.Context) error {
+	if err := s.deleteQosRules(ctx, consts.DSCP_MARKING_RULE, "dscp_marking"); err != nil {
+		return err
+	}
+	log.Println("Dscp marking rules were deleted completely")
+	return nil
+}
 
-	outputObj.Response = resp.StatusCode()
-	if resp.StatusCode() == fasthttp.StatusOK {
-		outputObj.Success = true
+func (s *Controller) DeleteMinimumBandwidthRules(ctx context.Context) error {
+	if err := s.deleteQosRules(ctx, consts.MINIMUM_BANDWIDTH_RULE, "minimum_bandwidth"); err != nil {
+		return err
 	}
-	return outputObj
+	log.Println("Minimum bandwidth rules were deleted completely")
+	return nil
+}
+
+func (s *Controller) listQoss(ctx context.Context, opts ...ListOption) (entity.QosPolicies, error) {
+	query, err := s.projectFilter(ctx)
+	if err != nil {
+		return entity.QosPolicies{}, err
+	}
+	policies, err := s.QosPolicies().List(ctx, query, opts...)
+	if err != nil {
+		return entity.QosPolicies{}, err
+	}
+	log.Println("==============List qos policy success, there had", len(policies))
+	return entity.QosPolicies{Qps: policies, Count: len(policies)}, nil
+}
+
+func (s *Controller) DeleteQos(ctx context.Context, qosId string) Output {
+	outputObj := Output{ParametersMap: map[string]string{"qos_policy_id": qosId}}
+	return resultOutput(outputObj, s.QosPolicies().Delete(ctx, qosId))
 }
 
-func (s *Controller) DeleteQosPolicies() {
-	qoses := s.listQoss()
+func (s *Controller) DeleteQosPolicies(ctx context.Context) error {
+	qoses, err := s.listQoss(ctx)
+	if err != nil {
+		return err
+	}
 	ch := s.MakeDeleteChannel(consts.QOS_POLICY, len(qoses.Qps))
 	for _, qos := range qoses.Qps {
 		tempQos := qos
 		go func() {
-			ch <- s.DeleteQos(tempQos.Id)
+			ch <- s.DeleteQos(ctx, tempQos.Id)
 		}()
 	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("Qos policies were deleted completely")
+	return nil
 }
 
-func (s *Controller) DeleteQosRule(ruleType, qosId, ruleId string) Output {
+func (s *Controller) DeleteQosRule(ctx context.Context, ruleType, qosId, ruleId string) Output {
 	var identity string
 	switch ruleType {
 	case "bandwidth_limit":
@@ -1203,24 +1296,8 @@ func (s *Controller) DeleteQosRule(ruleType, qosId, ruleId string) Output {
 		identity = consts.MINIMUM_BANDWIDTH_RULES
 	}
 	outputObj := Output{ParametersMap: map[string]string{"qos_policy_id": qosId, "rule_id": ruleId}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-			outputObj.Response = err
-		}
-	}()
-
-	urlSuffix := fmt.Sprintf("qos/policies/%s/%s/%s", qosId, identity, ruleId)
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		Resource: consts.QOS_POLICY, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	outputObj.Response = resp.StatusCode()
-	if resp.StatusCode() == fasthttp.StatusOK {
-		outputObj.Success = true
-	}
-	return outputObj
+	err := s.QosPolicies().call(ctx, DELETE, qosId, fmt.Sprintf("%s/%s", identity, ruleId), nil, nil)
+	return resultOutput(outputObj, err)
 }
 
 // rbac policy
@@ -1229,631 +1306,503 @@ func (s *Controller) DeleteQosRule(ruleType, qosId, ruleId string) Output {
 
 
 // Volume type
-func (s *Controller) createVolumeType(opts entity.CreateUpdateOptions) entity.VolumeType {
-	PostUrl := fmt.Sprintf("%s/types", s.projectID)
-	res := wrapper(constructVolumeTypeRequestOpts)(opts, &ExtraOption{
-		ResourceLocation: PostUrl})
-	defer fasthttp.ReleaseResponse(res)
-
-	var volumeType entity.VolumeType
-	_ = json.Unmarshal(res.Body(), &volumeType)
-	return volumeType
+func (s *Controller) createVolumeType(ctx context.Context, opts entity.CreateUpdateOptions) (entity.VolumeType, error) {
+	return s.VolumeTypes().Create(ctx, opts)
 }
 
-func (s *Controller) VolumeTypeAssociateQos(opts entity.CreateUpdateOptions, qosId, volTypeId string) {
-	URL := fmt.Sprintf("/%s/qos-specs/%s/associate?vol_type_id=%s", s.projectID, qosId, volTypeId)
-	resp := wrapper(constructListRequestOpts)(opts, &ExtraOption{
-		Resource: consts.QOSSPEC, ResourceLocation: URL})
-	defer fasthttp.ReleaseResponse(resp)
+func (s *Controller) VolumeTypeAssociateQos(ctx context.Context, opts entity.CreateUpdateOptions, qosId, volTypeId string) error {
+	return s.QosSpecs().call(ctx, GET, qosId, "associate?vol_type_id="+url.QueryEscape(volTypeId), opts, nil)
 }
 
-func (s *Controller) GetVolumeType(volumeTypeId string) entity.VolumeType {
-	suffix := fmt.Sprintf("%s/types/%s", s.projectID, volumeTypeId)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.VOLUMETYPE, ResourceLocation: suffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var volumeType entity.VolumeType
-	_ = json.Unmarshal(resp.Body(), &volumeType)
-
-	return volumeType
+func (s *Controller) GetVolumeType(ctx context.Context, volumeTypeId string) (entity.VolumeType, error) {
+	return s.VolumeTypes().Get(ctx, volumeTypeId)
 }
 
-func (s *Controller) ListVolumeTypes() entity.VolumeTypes {
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.VOLUMETYPE, ResourceLocation: fmt.Sprintf("%s/types", s.projectID)})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var volumeTypes entity.VolumeTypes
-	_ = json.Unmarshal(resp.Body(), &volumeTypes)
-
-	return volumeTypes
+func (s *Controller) ListVolumeTypes(ctx context.Context, opts ...ListOption) (entity.VolumeTypes, error) {
+	volumeTypes, err := s.VolumeTypes().List(ctx, "", opts...)
+	return entity.VolumeTypes{VTs: volumeTypes}, err
 }
 
-func (s *Controller) ListVolumeQos() entity.QosSpecss {
-	urlSuffix := fmt.Sprintf("/%s/qos-specs", s.projectID)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.QOSSPEC, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var qss entity.QosSpecss
-	_ = json.Unmarshal(resp.Body(), &qss)
+func (s *Controller) ListVolumeQos(ctx context.Context, opts ...ListOption) (entity.QosSpecss, error) {
+	qss, err := s.QosSpecs().List(ctx, "", opts...)
+	if err != nil {
+		return entity.QosSpecss{}, err
+	}
 	log.Println("==============Get qos specs success")
-	return qss
+	return entity.QosSpecss{Qss: qss}, nil
 }
 
-func (s *Controller) deleteVolumeType(typeId string)  {
-	urlSuffix := fmt.Sprintf("/%s/types/%s", s.projectID, typeId)
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		Resource: consts.VOLUMETYPE, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	log.Println("success delete", typeId)
+func (s *Controller) deleteVolumeType(ctx context.Context, typeId string) error {
+	return s.VolumeTypes().Delete(ctx, typeId)
 }
 
 
 // volume
 
 // CreateVolume create volume
-func (s *Controller) CreateVolume(opts entity.CreateUpdateOptions) string {
-	urlSuffix := fmt.Sprintf("/%s/volumes", s.projectID)
-	resp := wrapper(constructCreateVolumeRequestOpts)(opts, &ExtraOption{
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var volume entity.VolumeMap
-	_ = json.Unmarshal(resp.Body(), &volume)
-
-	s.MakeSureVolumeAvailable(volume.Id)
+func (s *Controller) CreateVolume(ctx context.Context, opts entity.CreateUpdateOptions) (string, error) {
+	volume, err := s.Volumes().Create(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+	if err = s.MakeSureVolumeAvailable(ctx, volume.Id); err != nil {
+		return volume.Id, err
+	}
 	log.Println("==============Create volume success", volume.Id)
-	return volume.Id
+	return volume.Id, nil
 }
 
 
-func (s *Controller) GetVolume(volumeId string) entity.VolumeMap {
-	urlSuffix := fmt.Sprintf("%s/volumes/%s", s.projectID, volumeId)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.VOLUME, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var volume entity.VolumeMap
-	_ = json.Unmarshal(resp.Body(), &volume)
+func (s *Controller) GetVolume(ctx context.Context, volumeId string) (entity.VolumeMap, error) {
+	volume, err := s.Volumes().Get(ctx, volumeId)
+	if err != nil {
+		return entity.VolumeMap{}, err
+	}
 	log.Println("==============Get volume success", volumeId)
-	return volume
-}
-
-func (s *Controller) MakeSureVolumeAvailable(volumeId string) {
-	volume := s.GetVolume(volumeId)
-	done := make(chan bool, 1)
-	go func() {
-		state := volume.Status
-		for state != consts.Available && state != consts.Error {
-			time.Sleep(consts.IntervalTime)
-			volume = s.GetVolume(volumeId)
-			state = volume.Status
+	return entity.VolumeMap{Volume: volume}, nil
+}
+
+func (s *Controller) MakeSureVolumeAvailable(ctx context.Context, volumeId string) error {
+	err := waitFor(ctx, "volume "+volumeId+" to become available", consts.IntervalTime, consts.Timeout, func() (bool, error) {
+		volume, err := s.GetVolume(ctx, volumeId)
+		if err != nil {
+			return false, err
 		}
-		done <- true
-	}()
-	select {
-	case <-done:
-		log.Println("*******************Create Volume success")
-	case <-time.After(consts.Timeout):
-		log.Fatalln("*******************Create volume timeout")
+		if volume.Status == consts.Error {
+			return false, fmt.Errorf("volume %s went to %s", volumeId, volume.Status)
+		}
+		return volume.Status == consts.Available, nil
+	})
+	if err != nil {
+		log.Println("*******************Create volume failed", err)
+		return err
 	}
+	log.Println("*******************Create Volume success")
+	return nil
 }
 
-func (s *Controller) DeleteVolume(volumeId string, ch chan Output) {
+func (s *Controller) DeleteVolume(ctx context.Context, volumeId string, ch chan Output) {
 	outputObj := Output{ParametersMap: map[string]string{"volume_id": volumeId}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-			outputObj.Response = err
-		}
-		ch <- outputObj
-	}()
-
-	urlSuffix := fmt.Sprintf("/%s/volumes/%s", s.projectID, volumeId)
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		Resource: consts.VOLUME, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
+	ch <- resultOutput(outputObj, s.Volumes().Delete(ctx, volumeId))
+}
 
-	outputObj.Response = resp.StatusCode()
-	if resp.StatusCode() == fasthttp.StatusOK {
-		outputObj.Success = true
+func (s *Controller) ListVolumes(ctx context.Context, opts ...ListOption) (entity.Volumes, error) {
+	volumes, err := s.Volumes().List(ctx, "", opts...)
+	if err != nil {
+		return entity.Volumes{}, err
 	}
+	log.Println("==============List volume success, there had", len(volumes))
+	return entity.Volumes{Vs: volumes}, nil
 }
 
-func (s *Controller) ListVolumes() entity.Volumes {
-	urlSuffix := fmt.Sprintf("/%s/volumes", s.projectID)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.VOLUME, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var volumes entity.Volumes
-	_ = json.Unmarshal(resp.Body(), &volumes)
-	log.Println("==============List volume success, there had", len(volumes.Vs))
-	return volumes
+// IterVolumes streams the volumes page by page.
+func (s *Controller) IterVolumes(ctx context.Context, opts ...ListOption) *Iterator[entity.Volumes] {
+	return newIterator[entity.Volumes](s.Volumes().pager(ctx, "", opts))
 }
 
 
-func (s *Controller) DeleteAttachment(attachmentId string) {
-	urlSuffix := fmt.Sprintf("%s/attachments/%s", s.projectID, attachmentId)
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		Resource: consts.ATTACHMENT, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	if resp.StatusCode() == fasthttp.StatusOK {
-		log.Println("==============Delete attachment success", attachmentId)
-		return
+func (s *Controller) DeleteAttachment(ctx context.Context, attachmentId string) error {
+	if err := s.CinderAttachments().Delete(ctx, attachmentId); err != nil {
+		log.Println("==============Delete attachment failed", attachmentId)
+		return err
 	}
-	log.Println("==============Delete attachment failed", attachmentId)
+	return nil
 }
 
-func (s *Controller) DeleteVolumes() {
-	volumes := s.ListVolumes()
+func (s *Controller) DeleteVolumes(ctx context.Context) error {
+	volumes, err := s.ListVolumes(ctx)
+	if err != nil {
+		return err
+	}
 	ch := s.MakeDeleteChannel(consts.VOLUME, len(volumes.Vs))
 	for _, volume := range volumes.Vs {
-		if len(volume.Attachments) != 0 {
-			for _, attachment := range volume.Attachments {
-				s.DeleteAttachment(attachment.AttachmentId)
+		tempVolume := volume
+		go func() {
+			if err := s.detachVolumeAttachments(ctx, tempVolume); err != nil {
+				ch <- resultOutput(Output{ParametersMap: map[string]string{"volume_id": tempVolume.Id}}, err)
+				return
 			}
-		}
-		go s.DeleteVolume(volume.Id, ch)
+			s.DeleteVolume(ctx, tempVolume.Id, ch)
+		}()
 	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("Volumes were deleted completely")
+	return nil
 }
 
 
 // snapshot
 
 // CreateSnapshot create snapshot from volume
-func (s *Controller) CreateSnapshot(opts entity.CreateUpdateOptions) string {
-	urlSuffix := fmt.Sprintf("/%s/snapshots", s.projectID)
-	resp := wrapper(constructCreateSnapshotRequestOpts)(opts, &ExtraOption{
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var snapshot entity.SnapshotMap
-	_ = json.Unmarshal(resp.Body(), &snapshot)
-	s.makeSureSnapshotAvailable(snapshot.Id)
-	return snapshot.Id
+func (s *Controller) CreateSnapshot(ctx context.Context, opts entity.CreateUpdateOptions) (string, error) {
+	snapshot, err := s.Snapshots().Create(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+	return snapshot.Id, s.makeSureSnapshotAvailable(ctx, snapshot.Id)
 }
 
-func (s *Controller) GetSnapshot(snapshotId string) entity.SnapshotMap {
-	urlSuffix := fmt.Sprintf("/%s/snapshots/%s", s.projectID, snapshotId)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.SNAPSHOT, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var snapshot entity.SnapshotMap
-	_ = json.Unmarshal(resp.Body(), &snapshot)
+func (s *Controller) GetSnapshot(ctx context.Context, snapshotId string) (entity.SnapshotMap, error) {
+	snapshot, err := s.Snapshots().Get(ctx, snapshotId)
+	if err != nil {
+		return entity.SnapshotMap{}, err
+	}
 	log.Println("==============Get snapshot success", snapshotId)
-	return snapshot
-}
-
-func (s *Controller) makeSureSnapshotAvailable(snapshotId string) {
-	snapshot := s.GetSnapshot(snapshotId)
-	done := make(chan bool, 1)
-	go func() {
-		state := snapshot.Status
-		for state != consts.Available && state != consts.Error {
-			time.Sleep(consts.IntervalTime)
-			snapshot = s.GetSnapshot(snapshotId)
-			state = snapshot.Status
+	return entity.SnapshotMap{Snapshot: snapshot}, nil
+}
+
+func (s *Controller) makeSureSnapshotAvailable(ctx context.Context, snapshotId string) error {
+	err := waitFor(ctx, "snapshot "+snapshotId+" to become available", consts.IntervalTime, consts.Timeout, func() (bool, error) {
+		snapshot, err := s.GetSnapshot(ctx, snapshotId)
+		if err != nil {
+			return false, err
+		}
+		if snapshot.Status == consts.Error {
+			return false, fmt.Errorf("snapshot %s went to %s", snapshotId, snapshot.Status)
 		}
-		done <- true
-	}()
-	select {
-	case <-done:
-		log.Println("*******************Create snapshot success")
-	case <-time.After(consts.Timeout):
-		log.Fatalln("*******************Create snapshot timeout")
+		return snapshot.Status == consts.Available, nil
+	})
+	if err != nil {
+		log.Println("*******************Create snapshot failed", err)
+		return err
 	}
+	log.Println("*******************Create snapshot success")
+	return nil
 }
 
-func (s *Controller) listProjectSnapshots() entity.Snapshots {
-	urlSuffix := fmt.Sprintf("/%s/snapshots", s.projectID)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.SNAPSHOT, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var ss entity.Snapshots
-	_ = json.Unmarshal(resp.Body(), &ss)
+func (s *Controller) listProjectSnapshots(ctx context.Context, opts ...ListOption) (entity.Snapshots, error) {
+	snapshots, err := s.Snapshots().List(ctx, "", opts...)
+	if err != nil {
+		return entity.Snapshots{}, err
+	}
 	log.Println("==============List snapshot success")
-	return ss
+	return entity.Snapshots{Ss: snapshots}, nil
 }
 
-func (s *Controller) DeleteSnapshot(snapshotId string, ch chan Output) {
+func (s *Controller) DeleteSnapshot(ctx context.Context, snapshotId string, ch chan Output) {
 	outputObj := Output{ParametersMap: map[string]string{"snapshot_id": snapshotId}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-			outputObj.Response = err
-		}
-		ch <- outputObj
-	}()
-
-	urlSuffix := fmt.Sprintf("/%s/snapshots/%s", s.projectID, snapshotId)
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		Resource: consts.SNAPSHOT, ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	outputObj.Response = resp.StatusCode()
-	if resp.StatusCode() == fasthttp.StatusOK {
-		outputObj.Success = true
-	}
+	ch <- resultOutput(outputObj, s.Snapshots().Delete(ctx, snapshotId))
 }
 
-func (s *Controller) DeleteSnapshots() {
-	snapshots := s.listProjectSnapshots()
+func (s *Controller) DeleteSnapshots(ctx context.Context) error {
+	snapshots, err := s.listProjectSnapshots(ctx)
+	if err != nil {
+		return err
+	}
 	ch := s.MakeDeleteChannel(consts.SNAPSHOT, len(snapshots.Ss))
 	for _, snapshot := range snapshots.Ss {
-		go s.DeleteSnapshot(snapshot.Id, ch)
+		go s.DeleteSnapshot(ctx, snapshot.Id, ch)
 	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("Snapshots were deleted completely")
+	return nil
 }
 
 
 // load balancer
 
-func (s *Controller) CreateLoadbalancer(opts entity.CreateLoadbalancerOpts) string {
+func (s *Controller) CreateLoadbalancer(ctx context.Context, opts entity.CreateLoadbalancerOpts) (string, error) {
 	opts.Name = fmt.Sprintf("%s_%s", opts.Name, consts.LOADBALANCER)
-	urlSuffix := "lbaas/loadbalancers"
-	resp := wrapper(constructCreateLoadBalancerRequestOpts)(nil, &ExtraOption{
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var lb entity.LoadbalancerMap
-	_ = json.Unmarshal(resp.Body(), &lb)
-
-	s.makeSureLbActive(lb.Loadbalancer.Id)
-	log.Println("==============create loadbalancer success", lb.Loadbalancer.Id)
-	return lb.Loadbalancer.Id
+	lb, err := s.Loadbalancers().Create(ctx, &opts)
+	if err != nil {
+		return "", err
+	}
+	if _, err = s.makeSureLbActive(ctx, lb.Id); err != nil {
+		return lb.Id, err
+	}
+	log.Println("==============create loadbalancer success", lb.Id)
+	return lb.Id, nil
 }
 
-func (s *Controller) deleteLoadbalancer(ipId string) Output {
+func (s *Controller) deleteLoadbalancer(ctx context.Context, ipId string) Output {
 	outputObj := Output{ParametersMap: map[string]string{"loadbalancer_id": ipId}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-			outputObj.Response = err
-		}
-	}()
-
-	urlSuffix := fmt.Sprintf("lbaas/loadbalancers/%s", ipId)
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		Resource: consts.LOADBALANCER,
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	if resp.StatusCode() == fasthttp.StatusOK {
-		outputObj.Success = true
+	outputObj = resultOutput(outputObj, s.Loadbalancers().Delete(ctx, ipId))
+	if !outputObj.Success {
+		return outputObj
+	}
+	if err := s.makeSureLbDeleted(ctx, ipId); err != nil {
+		log.Println("catch error：", err)
+		outputObj.Success = false
+		outputObj.Response = err
 	}
-	outputObj.Response = resp
-	s.makeSureLbDeleted(ipId)
 	return outputObj
 }
 
-func (s *Controller) getLoadbalancer(ipId string) entity.LoadbalancerMap {
-	urlSuffix := fmt.Sprintf("lbaas/loadbalancers/%s", ipId)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.LOADBALANCER,
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var lb entity.LoadbalancerMap
-	_ = json.Unmarshal(resp.Body(), &lb)
-	return lb
+func (s *Controller) getLoadbalancer(ctx context.Context, ipId string) (entity.LoadbalancerMap, error) {
+	lb, err := s.Loadbalancers().Get(ctx, ipId)
+	if err != nil {
+		return entity.LoadbalancerMap{}, err
+	}
+	return entity.LoadbalancerMap{Loadbalancer: lb}, nil
 }
 
-func (s *Controller) ListLoadbalancers() entity.Loadbalancers {
-	urlSuffix := "lbaas/loadbalancers"
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.LOADBALANCER,
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var lbs entity.Loadbalancers
-	_ = json.Unmarshal(resp.Body(), &lbs)
-	log.Println("==============List loadbalancers success, there had", len(lbs.LBs))
-	return lbs
+func (s *Controller) ListLoadbalancers(ctx context.Context, opts ...ListOption) (entity.Loadbalancers, error) {
+	lbs, err := s.Loadbalancers().List(ctx, "", opts...)
+	if err != nil {
+		return entity.Loadbalancers{}, err
+	}
+	log.Println("==============List loadbalancers success, there had", len(lbs))
+	return entity.Loadbalancers{LBs: lbs}, nil
 }
 
-func (s *Controller) DeleteLoadbalancers() {
-	lbs := s.ListLoadbalancers()
+func (s *Controller) DeleteLoadbalancers(ctx context.Context) error {
+	lbs, err := s.ListLoadbalancers(ctx)
+	if err != nil {
+		return err
+	}
 	ch := s.MakeDeleteChannel(consts.LOADBALANCER, len(lbs.LBs))
 
 	for _, lb := range lbs.LBs {
 		tempLb := lb
 		go func() {
-			ch <- s.deleteLoadbalancer(tempLb.Id)
+			ch <- s.deleteLoadbalancer(ctx, tempLb.Id)
 		}()
 	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("Loadbalancers were deleted completely")
+	return nil
 }
 
 // listener
 
-func (s *Controller) CreateListener(opts entity.CreateListenerOpts) string {
-	urlSuffix := "lbaas/listeners"
+func (s *Controller) CreateListener(ctx context.Context, opts entity.CreateListenerOpts) (string, error) {
 	opts.Name = fmt.Sprintf("%s_%s", opts.Name, consts.LISTENER)
-	resp := wrapper(constructCreateListenerRequestOpts)(nil, &ExtraOption{
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var listener entity.ListenerMap
-	_ = json.Unmarshal(resp.Body(), &listener)
-
-	s.makeSureLbActive(opts.LoadbalancerID)
-	log.Println("==============Create listener success", listener.Listener.Id)
-	return listener.Listener.Id
+	listener, err := s.Listeners().Create(ctx, &opts)
+	if err != nil {
+		return "", err
+	}
+	if _, err = s.makeSureLbActive(ctx, opts.LoadbalancerID); err != nil {
+		return listener.Id, err
+	}
+	log.Println("==============Create listener success", listener.Id)
+	return listener.Id, nil
 }
 
-func (s *Controller) deleteListener(listenerId string) Output {
+func (s *Controller) deleteListener(ctx context.Context, listenerId string) Output {
 	outputObj := Output{ParametersMap: map[string]string{"listener_id": listenerId}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-		}
-	}()
-
-	urlSuffix := fmt.Sprintf("lbaas/listeners/%s", listenerId)
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		Resource: consts.LISTENER,
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	if resp.StatusCode() == fasthttp.StatusOK {
-		outputObj.Success = true
-	}
-	outputObj.Response = resp
-	return outputObj
+	return resultOutput(outputObj, s.Listeners().Delete(ctx, listenerId))
 }
 
-func (s *Controller) getListener(ipId string) entity.ListenerMap {
-	urlSuffix := fmt.Sprintf("lbaas/listeners/%s", ipId)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.LISTENER,
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var listener entity.ListenerMap
-	_ = json.Unmarshal(resp.Body(), &listener)
-	return listener
+func (s *Controller) getListener(ctx context.Context, ipId string) (entity.ListenerMap, error) {
+	listener, err := s.Listeners().Get(ctx, ipId)
+	if err != nil {
+		return entity.ListenerMap{}, err
+	}
+	return entity.ListenerMap{Listener: listener}, nil
 }
 
-func (s *Controller) ListListeners() entity.Listeners {
-	urlSuffix := "lbaas/listeners"
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.LISTENER,
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var listeners entity.Listeners
-	_ = json.Unmarshal(resp.Body(), &listeners)
-	log.Println("==============List listeners success, there had", len(listeners.Liss))
-	return listeners
+func (s *Controller) ListListeners(ctx context.Context, opts ...ListOption) (entity.Listeners, error) {
+	listeners, err := s.Listeners().List(ctx, "", opts...)
+	if err != nil {
+		return entity.Listeners{}, err
+	}
+	log.Println("==============List listeners success, there had", len(listeners))
+	return entity.Listeners{Liss: listeners}, nil
 }
 
-func (s *Controller) DeleteListeners() {
-	listeners := s.ListListeners()
+func (s *Controller) DeleteListeners(ctx context.Context) error {
+	listeners, err := s.ListListeners(ctx)
+	if err != nil {
+		return err
+	}
 	ch := s.MakeDeleteChannel(consts.LISTENER, len(listeners.Liss))
 
 	for _, listener := range listeners.Liss {
 		temp := listener
 		go func() {
-			ch <- s.deleteListener(temp.Id)
+			ch <- s.deleteListener(ctx, temp.Id)
 		}()
 	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("Listeners were deleted completely")
+	return nil
 }
 
-func (s *Controller) makeSureLbActive(lbId string) entity.LoadbalancerMap {
-	lb := s.getLoadbalancer(lbId)
-
-	for lb.Loadbalancer.ProvisioningStatus != consts.ACTIVE {
-		time.Sleep(5 * time.Second)
-		lb = s.getLoadbalancer(lbId)
-	}
-	return lb
+func (s *Controller) makeSureLbActive(ctx context.Context, lbId string) (entity.LoadbalancerMap, error) {
+	var lb entity.LoadbalancerMap
+	err := waitFor(ctx, "loadbalancer "+lbId+" to become ACTIVE", 5*time.Second, consts.Timeout, func() (bool, error) {
+		var err error
+		if lb, err = s.getLoadbalancer(ctx, lbId); err != nil {
+			return false, err
+		}
+		if lb.Loadbalancer.ProvisioningStatus == consts.Error {
+			return false, fmt.Errorf("loadbalancer %s went to %s", lbId, lb.Loadbalancer.ProvisioningStatus)
+		}
+		return lb.Loadbalancer.ProvisioningStatus == consts.ACTIVE, nil
+	})
+	return lb, err
 }
 
-func (s *Controller) makeSureLbDeleted(lbId string) {
-	lb := s.getLoadbalancer(lbId)
-	for &lb != nil {
-		time.Sleep(5 * time.Second)
-		lb = s.getLoadbalancer(lbId)
+func (s *Controller) makeSureLbDeleted(ctx context.Context, lbId string) error {
+	err := waitFor(ctx, "loadbalancer "+lbId+" to be deleted", 5*time.Second, consts.Timeout, func() (bool, error) {
+		_, err := s.getLoadbalancer(ctx, lbId)
+		if client.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if err != nil {
+		return err
 	}
 	log.Println("*******************Lb was deleted success")
+	return nil
 }
 
 // pool
 
-func (s *Controller) CreatePool(opts entity.CreatePoolOpts) string {
-	urlSuffix := "lbaas/pools"
+func (s *Controller) CreatePool(ctx context.Context, opts entity.CreatePoolOpts) (string, error) {
 	opts.Name = fmt.Sprintf("%s_%s", opts.Name, consts.POOL)
-	resp := wrapper(constructCreatePoolRequestOpts)(nil, &ExtraOption{
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var pool entity.PoolMap
-	_ = json.Unmarshal(resp.Body(), &pool)
-
-	s.makeSurePoolActive(pool.Pool.Id)
-	for _, lb := range pool.Pool.Loadbalancers {
-		s.makeSureLbActive(lb.Id)
+	pool, err := s.Pools().Create(ctx, &opts)
+	if err != nil {
+		return "", err
+	}
+	if _, err = s.makeSurePoolActive(ctx, pool.Id); err != nil {
+		return pool.Id, err
+	}
+	for _, lb := range pool.Loadbalancers {
+		if _, err = s.makeSureLbActive(ctx, lb.Id); err != nil {
+			return pool.Id, err
+		}
 	}
-	log.Println("==============Create pool success", pool.Pool.Id)
-	return pool.Pool.Id
+	log.Println("==============Create pool success", pool.Id)
+	return pool.Id, nil
 }
 
-func (s *Controller) deletePool(poolId string) Output {
-	pool := s.getPool(poolId)
+func (s *Controller) deletePool(ctx context.Context, poolId string) Output {
 	outputObj := Output{ParametersMap: map[string]string{"pool_id": poolId}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-		}
-	}()
-
-	urlSuffix := fmt.Sprintf( "lbaas/pools/%s", poolId)
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		Resource: consts.POOL,
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
+	pool, err := s.getPool(ctx, poolId)
+	if err != nil {
+		return resultOutput(outputObj, err)
+	}
 
-	if resp.StatusCode() == fasthttp.StatusOK {
-		outputObj.Success = true
+	outputObj = resultOutput(outputObj, s.Pools().Delete(ctx, poolId))
+	if !outputObj.Success {
+		return outputObj
 	}
-	outputObj.Response = resp
 	for _, lb := range pool.Loadbalancers {
-		s.makeSureLbActive(lb.Id)
+		if _, err = s.makeSureLbActive(ctx, lb.Id); err != nil {
+			log.Println("catch error：", err)
+		}
 	}
 	return outputObj
 }
 
-func (s *Controller) getPool(ipId string) entity.PoolMap {
-	urlSuffix := fmt.Sprintf("lbaas/pools/%s", ipId)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.POOL,
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var pool entity.PoolMap
-	_ = json.Unmarshal(resp.Body(), &pool)
-	return pool
+func (s *Controller) getPool(ctx context.Context, ipId string) (entity.PoolMap, error) {
+	pool, err := s.Pools().Get(ctx, ipId)
+	if err != nil {
+		return entity.PoolMap{}, err
+	}
+	return entity.PoolMap{Pool: pool}, nil
 }
 
-func (s *Controller) ListPools() entity.Pools {
-	urlSuffix := "lbaas/pools"
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.POOL,
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var pools entity.Pools
-	_ = json.Unmarshal(resp.Body(), &pools)
-	log.Println("==============List pools success, there had", len(pools.Ps))
-	return pools
+func (s *Controller) ListPools(ctx context.Context, opts ...ListOption) (entity.Pools, error) {
+	pools, err := s.Pools().List(ctx, "", opts...)
+	if err != nil {
+		return entity.Pools{}, err
+	}
+	log.Println("==============List pools success, there had", len(pools))
+	return entity.Pools{Ps: pools}, nil
 }
 
-func (s *Controller) DeletePools() {
-	pools := s.ListPools()
+func (s *Controller) DeletePools(ctx context.Context) error {
+	pools, err := s.ListPools(ctx)
+	if err != nil {
+		return err
+	}
 	ch := s.MakeDeleteChannel(consts.POOL, len(pools.Ps))
 	for _, pool := range pools.Ps {
 		//for _, member := range pool.Members {
-		//	s.deletePoolMember(pool, member.(map[string]interface{})["id"].(string))
+		//	s.deletePoolMember(ctx, pool, member.(map[string]interface{})["id"].(string))
 		//}
 		temp := pool
 		go func() {
-			ch <- s.deletePool(temp.Id)
+			ch <- s.deletePool(ctx, temp.Id)
 		}()
 	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("Pool were deleted completely")
+	return nil
 }
 
-func (s *Controller) makeSurePoolActive(poolId string) entity.PoolMap {
-	pool := s.getPool(poolId)
-
-	for pool.Pool.ProvisioningStatus != consts.ACTIVE {
-		time.Sleep(5 * time.Second)
-		pool = s.getPool(poolId)
-	}
-	return pool
+func (s *Controller) makeSurePoolActive(ctx context.Context, poolId string) (entity.PoolMap, error) {
+	var pool entity.PoolMap
+	err := waitFor(ctx, "pool "+poolId+" to become ACTIVE", 5*time.Second, consts.Timeout, func() (bool, error) {
+		var err error
+		if pool, err = s.getPool(ctx, poolId); err != nil {
+			return false, err
+		}
+		if pool.Pool.ProvisioningStatus == consts.Error {
+			return false, fmt.Errorf("pool %s went to %s", poolId, pool.Pool.ProvisioningStatus)
+		}
+		return pool.Pool.ProvisioningStatus == consts.ACTIVE, nil
+	})
+	return pool, err
 }
 
 // pool member
 
-func (s *Controller) CreatePoolMember(poolId string, opts entity.CreateMemberOpts) string {
-	urlSuffix := fmt.Sprintf("lbaas/pools/%s/members", poolId)
+func (s *Controller) CreatePoolMember(ctx context.Context, poolId string, opts entity.CreateMemberOpts) (string, error) {
 	opts.Name = fmt.Sprintf("%s_%s", opts.Name, consts.POOL)
-	resp := wrapper(constructCreatePoolMemberRequestOpts)(nil, &ExtraOption{
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var member entity.MemberMap
-	_ = json.Unmarshal(resp.Body(), &member)
-
-	s.makeSurePoolActive(poolId)
-	log.Println("==============Create member success", member.Member.Id)
-	return member.Member.Id
+	member, err := s.PoolMembers(poolId).Create(ctx, &opts)
+	if err != nil {
+		return "", err
+	}
+	if _, err = s.makeSurePoolActive(ctx, poolId); err != nil {
+		return member.Id, err
+	}
+	log.Println("==============Create member success", member.Id)
+	return member.Id, nil
 }
 
-func (s *Controller) deletePoolMember(pool entity.Pool, memberId string) Output {
+func (s *Controller) deletePoolMember(ctx context.Context, pool entity.Pool, memberId string) Output {
 	defer s.mu.Unlock()
 	s.mu.Lock()
 	outputObj := Output{ParametersMap: map[string]string{"member_id": memberId}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-		}
-	}()
-
-	urlSuffix := fmt.Sprintf("lbaas/pools/%s/members/%s", pool.Id, memberId)
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		Resource: consts.MEMBER,
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	if resp.StatusCode() == fasthttp.StatusOK {
-		outputObj.Success = true
+	outputObj = resultOutput(outputObj, s.PoolMembers(pool.Id).Delete(ctx, memberId))
+	if !outputObj.Success {
+		return outputObj
+	}
+	if _, err := s.makeSurePoolActive(ctx, pool.Id); err != nil {
+		log.Println("catch error：", err)
 	}
-	outputObj.Response = resp
-	s.makeSurePoolActive(pool.Id)
 	for _, lb := range pool.Loadbalancers {
-		s.makeSureLbActive(lb.Id)
+		if _, err := s.makeSureLbActive(ctx, lb.Id); err != nil {
+			log.Println("catch error：", err)
+		}
 	}
 	return outputObj
 }
 
-func (s *Controller) getPoolMember(poolId, memberId string) entity.MemberMap {
-	urlSuffix := fmt.Sprintf("lbaas/pools/%s/members/%s", poolId, memberId)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.MEMBER,
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var member entity.MemberMap
-	_ = json.Unmarshal(resp.Body(), &member)
-	return member
+func (s *Controller) getPoolMember(ctx context.Context, poolId, memberId string) (entity.MemberMap, error) {
+	member, err := s.PoolMembers(poolId).Get(ctx, memberId)
+	return entity.MemberMap{Member: member}, err
 }
 
-func (s *Controller) ListPoolMembers(poolId string) entity.Members {
-	urlSuffix := fmt.Sprintf("lbaas/pools/%s/members", poolId)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.MEMBER,
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-	var ms entity.Members
-	_ = json.Unmarshal(resp.Body(), &ms)
-	log.Println("==============List pool members success, there had", len(ms.Ms))
-	return ms
+func (s *Controller) ListPoolMembers(ctx context.Context, poolId string, opts ...ListOption) (entity.Members, error) {
+	members, err := s.PoolMembers(poolId).List(ctx, "", opts...)
+	if err != nil {
+		return entity.Members{}, err
+	}
+	log.Println("==============List pool members success, there had", len(members))
+	return entity.Members{Ms: members}, nil
 }
 
 
-func (s *Controller) DeleteMembers() {
-	pools := s.ListPools()
+func (s *Controller) DeleteMembers(ctx context.Context) error {
+	pools, err := s.ListPools(ctx)
+	if err != nil {
+		return err
+	}
 	var memberNumber int
 	for _, pool := range pools.Ps {
 		memberNumber += len(pool.Members)
@@ -1864,237 +1813,176 @@ func (s *Controller) DeleteMembers() {
 		for _, member := range pool.Members {
 			temp := member
 			go func() {
-				ch <- s.deletePoolMember(tempPool, temp.Id)
+				ch <- s.deletePoolMember(ctx, tempPool, temp.Id)
 			}()
 		}
 	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("Pool members were deleted completely")
+	return nil
 }
 
 // health monitor
 
-func (s *Controller) CreateHealthMonitor(opts entity.CreateHealthMonitorOpts) string {
-	urlSuffix := "lbaas/healthmonitors"
+func (s *Controller) CreateHealthMonitor(ctx context.Context, opts entity.CreateHealthMonitorOpts) (string, error) {
 	opts.Name = fmt.Sprintf("%s_%s", opts.Name, consts.HEALTHMONITOR)
-	resp := wrapper(constructCreateHealthMonitorRequestOpts)(nil, &ExtraOption{
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var healthMonitor entity.HealthMonitorMap
-	_ = json.Unmarshal(resp.Body(), &healthMonitor)
-
-	log.Println("==============Create health monitor success", healthMonitor.Healthmonitor.Id)
-	return healthMonitor.Healthmonitor.Id
+	healthMonitor, err := s.HealthMonitors().Create(ctx, &opts)
+	if err != nil {
+		return "", err
+	}
+	log.Println("==============Create health monitor success", healthMonitor.Id)
+	return healthMonitor.Id, nil
 }
 
-func (s *Controller) deleteHealthMonitor(healthmonitorId string) Output {
+func (s *Controller) deleteHealthMonitor(ctx context.Context, healthmonitorId string) Output {
 	outputObj := Output{ParametersMap: map[string]string{"health_monitor_id": healthmonitorId}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-		}
-	}()
-
-	urlSuffix := fmt.Sprintf("lbaas/healthmonitors/%s", healthmonitorId)
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	if resp.StatusCode() == fasthttp.StatusOK {
-		outputObj.Success = true
-	}
-	outputObj.Response = resp
-	return outputObj
+	return resultOutput(outputObj, s.HealthMonitors().Delete(ctx, healthmonitorId))
 }
 
-func (s *Controller) getHealthMonitor(healthmonitorId string) entity.HealthMonitorMap {
-	urlSuffix := fmt.Sprintf("lbaas/healthmonitors/%s", healthmonitorId)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.HEALTHMONITOR,
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var healthmonitor entity.HealthMonitorMap
-	_ = json.Unmarshal(resp.Body(), &healthmonitor)
-	return healthmonitor
+func (s *Controller) getHealthMonitor(ctx context.Context, healthmonitorId string) (entity.HealthMonitorMap, error) {
+	healthmonitor, err := s.HealthMonitors().Get(ctx, healthmonitorId)
+	if err != nil {
+		return entity.HealthMonitorMap{}, err
+	}
+	return entity.HealthMonitorMap{Healthmonitor: healthmonitor}, nil
 }
 
-func (s *Controller) ListHealthMonitors() entity.HealthMonitors {
-	urlSuffix := "lbaas/healthmonitors"
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		Resource: consts.HEALTHMONITOR,
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var hms entity.HealthMonitors
-	_ = json.Unmarshal(resp.Body(), &hms)
-	log.Println("==============List health monitors success, there had", len(hms.HMs))
-	return hms
+func (s *Controller) ListHealthMonitors(ctx context.Context, opts ...ListOption) (entity.HealthMonitors, error) {
+	hms, err := s.HealthMonitors().List(ctx, "", opts...)
+	if err != nil {
+		return entity.HealthMonitors{}, err
+	}
+	log.Println("==============List health monitors success, there had", len(hms))
+	return entity.HealthMonitors{HMs: hms}, nil
 }
 
-func (s *Controller) DeleteHealthmonitors() {
-	healthmonitors := s.ListHealthMonitors()
+func (s *Controller) DeleteHealthmonitors(ctx context.Context) error {
+	healthmonitors, err := s.ListHealthMonitors(ctx)
+	if err != nil {
+		return err
+	}
 	ch := s.MakeDeleteChannel(consts.HEALTHMONITOR, len(healthmonitors.HMs))
 	for _, healthmonitor := range healthmonitors.HMs {
 		temp := healthmonitor
 		go func() {
-			ch <- s.deleteHealthMonitor(temp.Id)
+			ch <- s.deleteHealthMonitor(ctx, temp.Id)
 		}()
 	}
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	for _, healthmonitor := range healthmonitors.HMs {
 		for _, pool := range healthmonitor.Pools {
-			s.makeSurePoolActive(pool.Id)
+			if _, err := s.makeSurePoolActive(ctx, pool.Id); err != nil {
+				return err
+			}
 		}
 	}
 	log.Println("Health monitors were deleted completely")
+	return nil
 }
 
 // L7 policy
 
-func (s *Controller) CreateL7Policy(listenerId string, opts entity.CreateUpdateOptions) string {
-	urlSuffix := "lbaas/l7policies"
-	resp := wrapper(constructCreateL7PolicyRequestOpts)(opts, &ExtraOption{
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var l7policy entity.L7PolicyMap
-	_ = json.Unmarshal(resp.Body(), &l7policy)
-
-	s.makeSureL7PolicyActive(l7policy.L7Policy.Id)
-	log.Println("==============create l7policy success", l7policy.L7Policy.Id)
-	return l7policy.L7Policy.Id
+func (s *Controller) CreateL7Policy(ctx context.Context, listenerId string, opts *entity.CreateL7PoliciesOpts) (string, error) {
+	l7policy, err := s.L7Policies().Create(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+	if _, err = s.makeSureL7PolicyActive(ctx, l7policy.Id); err != nil {
+		return l7policy.Id, err
+	}
+	log.Println("==============create l7policy success", l7policy.Id)
+	return l7policy.Id, nil
 }
 
-func (s *Controller) deleteL7Policy(l7policyId string) Output {
+func (s *Controller) deleteL7Policy(ctx context.Context, l7policyId string) Output {
 	outputObj := Output{ParametersMap: map[string]string{"l7Policy_id": l7policyId}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-		}
-	}()
-
-	urlSuffix := fmt.Sprintf("lbaas/l7policies/%s", l7policyId)
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
+	return resultOutput(outputObj, s.L7Policies().Delete(ctx, l7policyId))
+}
 
-	if resp.StatusCode() == fasthttp.StatusOK {
-		outputObj.Success = true
+func (s *Controller) getL7Policy(ctx context.Context, l7policyId string) (entity.L7PolicyMap, error) {
+	l7policy, err := s.L7Policies().Get(ctx, l7policyId)
+	if err != nil {
+		return entity.L7PolicyMap{}, err
 	}
-	outputObj.Response = resp
-	return outputObj
+	return entity.L7PolicyMap{L7Policy: l7policy}, nil
 }
 
-func (s *Controller) getL7Policy(l7policyId string) entity.L7PolicyMap {
-	urlSuffix := fmt.Sprintf("lbaas/l7policies/%s", l7policyId)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.L7POLICY,
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var l7policy entity.L7PolicyMap
-	_ = json.Unmarshal(resp.Body(), &l7policy)
-	return l7policy
+func (s *Controller) ListL7Policies(ctx context.Context, opts ...ListOption) (entity.L7Policies, error) {
+	l7ps, err := s.L7Policies().List(ctx, "", opts...)
+	if err != nil {
+		return entity.L7Policies{}, err
+	}
+	log.Println("==============List l7 policies success, there had", len(l7ps))
+	return entity.L7Policies{L7Ps: l7ps}, nil
 }
 
-func (s *Controller) ListL7Policies() entity.L7Policies {
-	urlSuffix := "lbaas/l7policies"
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		Resource: consts.L7POLICY,
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var l7ps entity.L7Policies
-	_ = json.Unmarshal(resp.Body(), &l7ps)
-	log.Println("==============List l7 policies success, there had", len(l7ps.L7Ps))
-	return l7ps
+func (s *Controller) makeSureL7PolicyActive(ctx context.Context, l7PolicyId string) (entity.L7PolicyMap, error) {
+	var l7Policy entity.L7PolicyMap
+	err := waitFor(ctx, "l7 policy "+l7PolicyId+" to become ACTIVE", 5*time.Second, consts.Timeout, func() (bool, error) {
+		var err error
+		if l7Policy, err = s.getL7Policy(ctx, l7PolicyId); err != nil {
+			return false, err
+		}
+		if l7Policy.L7Policy.ProvisioningStatus == consts.Error {
+			return false, fmt.Errorf("l7 policy %s went to %s", l7PolicyId, l7Policy.L7Policy.ProvisioningStatus)
+		}
+		return l7Policy.L7Policy.ProvisioningStatus == consts.ACTIVE, nil
+	})
+	return l7Policy, err
 }
 
-func (s *Controller) makeSureL7PolicyActive(l7PolicyId string) entity.L7PolicyMap {
-	l7Policy := s.getL7Policy(l7PolicyId)
-
-	for l7Policy.L7Policy.ProvisioningStatus != consts.ACTIVE {
-		time.Sleep(5 * time.Second)
-		l7Policy = s.getL7Policy(l7PolicyId)
+func (s *Controller) DeleteL7Policies(ctx context.Context) error {
+	l7policies, err := s.ListL7Policies(ctx)
+	if err != nil {
+		return err
 	}
-	return l7Policy
-}
-
-func (s *Controller) DeleteL7Policies() {
-	l7policies := s.ListL7Policies()
 	ch := s.MakeDeleteChannel(consts.L7POLICY, len(l7policies.L7Ps))
 	for _, l7policy := range l7policies.L7Ps {
 		temp := l7policy
 		go func() {
-			ch <- s.deleteL7Policy(temp.Id)
+			ch <- s.deleteL7Policy(ctx, temp.Id)
 		}()
 	}
 
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("L7 policies were deleted completely")
+	return nil
 }
 
 // L7 rule
 
-func (s *Controller) CreateL7Rule(policyId string, opts entity.CreateUpdateOptions) string {
-	urlSuffix := fmt.Sprintf("lbaas/l7policies/%s/rules", policyId)
-	resp := wrapper(constructCreateL7RuleRequestOpts)(opts, &ExtraOption{
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
+func (s *Controller) CreateL7Rule(ctx context.Context, policyId string, opts *entity.CreateRuleOpts) (string, error) {
 	var l7Rule entity.L7RuleMap
-	_ = json.Unmarshal(resp.Body(), &l7Rule)
-
+	if err := s.L7Policies().call(ctx, CREATE, policyId, "rules", opts, &l7Rule); err != nil {
+		return "", err
+	}
 	log.Println("==============create l7Rule success", l7Rule.Rule.Id)
-	return l7Rule.Rule.Id
+	return l7Rule.Rule.Id, nil
 }
 
-func (s *Controller) deleteL7Rule(l7PolicyId, l7RuleId string) Output {
+func (s *Controller) deleteL7Rule(ctx context.Context, l7PolicyId, l7RuleId string) Output {
 	outputObj := Output{ParametersMap: map[string]string{"l7Rule_id": l7RuleId}}
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("catch error：", err)
-			outputObj.Success = false
-		}
-	}()
-
-	urlSuffix := fmt.Sprintf("lbaas/l7policies/%s/rules/%s", l7PolicyId, l7RuleId)
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		Resource: consts.L7RULE,
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	if resp.StatusCode() == fasthttp.StatusOK {
-		outputObj.Success = true
-	}
-	outputObj.Response = resp
-	return outputObj
+	err := s.L7Policies().call(ctx, DELETE, l7PolicyId, "rules/"+l7RuleId, nil, nil)
+	return resultOutput(outputObj, err)
 }
 
-func (s *Controller) getL7Rule(l7PolicyId, l7RuleId string) entity.L7RuleMap {
-	urlSuffix := fmt.Sprintf("lbaas/l7policies/%s/rules/%s", l7PolicyId, l7RuleId)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.L7RULE,
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
+func (s *Controller) getL7Rule(ctx context.Context, l7PolicyId, l7RuleId string) (entity.L7RuleMap, error) {
 	var l7Rule entity.L7RuleMap
-	_ = json.Unmarshal(resp.Body(), &l7Rule)
-	return l7Rule
+	err := s.L7Policies().call(ctx, GET, l7PolicyId, "rules/"+l7RuleId, nil, &l7Rule)
+	return l7Rule, err
 }
 
-func (s *Controller) DeleteL7Rules() {
-	l7policies := s.ListL7Policies()
+func (s *Controller) DeleteL7Rules(ctx context.Context) error {
+	l7policies, err := s.ListL7Policies(ctx)
+	if err != nil {
+		return err
+	}
 	var ruleNumber int
 	for _, policy := range l7policies.L7Ps {
 		ruleNumber += len(policy.Rules)
@@ -2105,70 +1993,87 @@ func (s *Controller) DeleteL7Rules() {
 		for _, rule := range l7policy.Rules {
 			temp := rule
 			go func() {
-				ch <- s.deleteL7Rule(tempPolicy.Id, temp.Id)
+				ch <- s.deleteL7Rule(ctx, tempPolicy.Id, temp.Id)
 			}()
 		}
 	}
 
-	if len(ch) != cap(ch) {
-		for len(ch) != cap(ch) {
-		}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
 	}
 	log.Println("L7 rules were deleted completely")
+	return nil
 }
 
 // image
 
-func (s *Controller) CreateImage(opts entity.CreateUpdateOptions) string {
-	createSuffix := "/images"
-	resp := wrapper(constructCreateImageRequestOpts)(opts, &ExtraOption{
-		ResourceLocation: createSuffix})
-	defer fasthttp.ReleaseResponse(resp)
-
-	var image entity.ImageMap
-	_ = json.Unmarshal(resp.Body(), &image)
+func (s *Controller) CreateImage(ctx context.Context, opts *entity.CreateImageOpts) (string, error) {
+	image, err := s.Images().Create(ctx, opts)
+	if err != nil {
+		return "", err
+	}
 	log.Println("==============Create image success", image.Id)
-	return image.Id
+	return image.Id, nil
 }
 
-func (s *Controller) GetImage(imageId string) entity.ImageMap {
-	suffix := fmt.Sprintf("/images/%s", imageId)
-	resp := wrapper(constructListRequestOpts)(nil, &ExtraOption{
-		Resource: consts.Image,
-		ResourceLocation: suffix})
-	defer fasthttp.ReleaseResponse(resp)
+func (s *Controller) GetImage(ctx context.Context, imageId string) (entity.ImageMap, error) {
+	image, err := s.Images().Get(ctx, imageId)
+	if err == nil {
+		log.Println("==============Get image success")
+	}
+	return image, err
+}
 
-	var image entity.ImageMap
-	_ = json.Unmarshal(resp.Body(), &image)
-	log.Println("==============Get image success")
-	return image
+// ownerFilter is the query restricting the images to those owned by the
+// controller's project.
+func (s *Controller) ownerFilter(ctx context.Context) (string, error) {
+	projectID, err := s.Project(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("owner=%s", projectID), nil
 }
 
-func (s *Controller) GetImages() entity.Images {
-	suffix := fmt.Sprintf("/images?project_id=%s", s.projectID)
-	resp := wrapper(constructCreateImageRequestOpts)(nil, &ExtraOption{
-		Resource: consts.Image,
-		ResourceLocation: suffix})
-	defer fasthttp.ReleaseResponse(resp)
+func (s *Controller) imagesPager(ctx context.Context, opts []ListOption) (*pager, error) {
+	query, err := s.ownerFilter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.Images().pager(ctx, query, opts)
+}
+
+func (s *Controller) GetImages(ctx context.Context, opts ...ListOption) (entity.Images, error) {
+	query, err := s.ownerFilter(ctx)
+	if err != nil {
+		return entity.Images{}, err
+	}
+	images, err := s.Images().List(ctx, query, opts...)
+	if err != nil {
+		return entity.Images{}, err
+	}
+	log.Println("==============List image success", len(images))
+	return entity.Images{Is: images}, nil
+}
 
-	var images entity.Images
-	_ = json.Unmarshal(resp.Body(), &images)
-	log.Println("==============List image success", images.Is)
-	return images
+// IterImages streams the images owned by the project page by page.
+func (s *Controller) IterImages(ctx context.Context, opts ...ListOption) *Iterator[entity.Images] {
+	return newIterator[entity.Images](s.imagesPager(ctx, opts))
 }
 
 
-func (s *Controller) DeleteImage(imageId string) {
-	urlSuffix := "/images/" + imageId
-	resp := wrapper(constructDeleteRequestOpts)(nil, &ExtraOption{
-		Resource: consts.Image,
-		ResourceLocation: urlSuffix})
-	defer fasthttp.ReleaseResponse(resp)
+func (s *Controller) DeleteImage(ctx context.Context, imageId string) error {
+	return s.Images().Delete(ctx, imageId)
 }
 
-func (s *Controller) DeleteImages() {
-	images := s.GetImages()
+func (s *Controller) DeleteImages(ctx context.Context) error {
+	images, err := s.GetImages(ctx)
+	if err != nil {
+		return err
+	}
 	for _, image := range images.Is {
-		s.DeleteImage(image.Id)
+		if err := s.DeleteImage(ctx, image.Id); err != nil {
+			return err
+		}
 	}
+	return nil
 }
@@ -1,8 +1,8 @@
 package service
 
 import (
+	"context"
 	"fmt"
-	"go-openstackclient/configs"
 	"go-openstackclient/internal/entity"
 	"math/rand"
 	"time"
@@ -13,93 +13,114 @@ var (
 	defaultController = NewController(defaultName)
 )
 
-func CreateNetworkHelper() string {
+func CreateNetworkHelper(ctx context.Context) (string, error) {
 	defaultOpts := defaultNetworkOpts()
-    network := defaultController.CreateNetwork(defaultOpts)
-	return network.Id
+	network, err := defaultController.CreateNetwork(ctx, defaultOpts)
+	return network.Id, err
 }
 
-func ListNetworkHelper() entity.Networks {
-	networks := defaultController.ListNetworks()
-	return networks
+func ListNetworkHelper(ctx context.Context) (entity.Networks, error) {
+	return defaultController.ListNetworks(ctx)
 }
 
-func CreateSubnetHelper(networkId string) string {
+func CreateSubnetHelper(ctx context.Context, networkId string) (string, error) {
 	defaultOpts := defaultSubnetOpts(networkId)
-	subnet := defaultController.CreateSubnet(defaultOpts)
-	return subnet.Id
+	subnet, err := defaultController.CreateSubnet(ctx, defaultOpts)
+	return subnet.Id, err
 }
 
-func CreateSecurityGroupHelper() string {
+func CreateSecurityGroupHelper(ctx context.Context) (string, error) {
 	defaultSg := defaultSgOpts()
-    sg := defaultController.CreateSecurityGroup(defaultSg)
-	return sg.Id
+	sg, err := defaultController.CreateSecurityGroup(ctx, defaultSg)
+	return sg.Id, err
 }
 
-func CreateSecurityRuleICMP(sgId string) {
+func CreateSecurityRuleICMP(ctx context.Context, sgId string) error {
 	ingressICMP := defaultICMPIngressSgRuleOpts(sgId)
-	defaultController.CreateSecurityRule(ingressICMP)
+	if _, err := defaultController.CreateSecurityRule(ctx, ingressICMP); err != nil {
+		return err
+	}
 
 	egressICMP := defaultICMPEgressSgRuleOpts(sgId)
-	defaultController.CreateSecurityRule(egressICMP)
+	_, err := defaultController.CreateSecurityRule(ctx, egressICMP)
+	return err
 }
 
 
-func CreateSecurityRuleSSH(sgId string) {
+func CreateSecurityRuleSSH(ctx context.Context, sgId string) error {
 	ingressSSH := defaultSSHIngressSgRuleOpts(sgId)
-	defaultController.CreateSecurityRule(ingressSSH)
+	if _, err := defaultController.CreateSecurityRule(ctx, ingressSSH); err != nil {
+		return err
+	}
 
 	egressSSH := defaultSSHEgressSgRuleOpts(sgId)
-	defaultController.CreateSecurityRule(egressSSH)
+	_, err := defaultController.CreateSecurityRule(ctx, egressSSH)
+	return err
 }
 
-func CreatePortHelper(networkId, subnetId string) string {
+func CreatePortHelper(ctx context.Context, networkId, subnetId string) (string, error) {
 	defaultOpts := defaultPortOpts(networkId, subnetId)
-    port := defaultController.CreatePort(defaultOpts)
-    return port.Id
+	port, err := defaultController.CreatePort(ctx, defaultOpts)
+	return port.Id, err
 }
 
-func CreateRouterHelper() string {
+func CreateRouterHelper(ctx context.Context) (string, error) {
 	createOpts := defaultRouterOpts()
-    router := defaultController.CreateRouter(createOpts)
-	return router.Id
+	router, err := defaultController.CreateRouter(ctx, createOpts)
+	return router.Id, err
 }
 
-func SetRouterGatewayHelper(routerId, extNetId string) {
+func SetRouterGatewayHelper(ctx context.Context, routerId, extNetId string) error {
 	createOpts := defaultRouterGatewayOpts(extNetId)
-    defaultController.SetRouterGateway(createOpts, routerId)
+	_, err := defaultController.SetRouterGateway(ctx, createOpts, routerId)
+	return err
 }
 
-func AddRouterInterfaceHelper(routerId, subnetId string) {
-    opts := defaultRouterInterfaceOpts(routerId, subnetId)
-	defaultController.AddRouterInterface(opts)
+func AddRouterInterfaceHelper(ctx context.Context, routerId, subnetId string) error {
+	opts := defaultRouterInterfaceOpts(routerId, subnetId)
+	_, err := defaultController.AddRouterInterface(ctx, opts)
+	return err
 }
 
-func CreateInstanceHelper(netId, sgName string) string {
-	defaultController.EnsureSgExist(sgName)
-	opts := defaultInstanceOpts(netId, sgName)
-	server := defaultController.CreateInstance(opts)
-	return server.Id
+// CreateInstanceHelper boots a server with the keypair keyName, the KeyName
+// of the profile when it is empty. The servers have no password login, so
+// it fails when neither names a keypair.
+func CreateInstanceHelper(ctx context.Context, netId, sgName, keyName string) (string, error) {
+	if keyName == "" {
+		keyName = defaultController.config().KeyName
+	}
+	if keyName == "" {
+		return "", fmt.Errorf("no keypair to boot the server with, pass one or set key_name in the profile")
+	}
+	if err := defaultController.EnsureSgExist(ctx, sgName); err != nil {
+		return "", err
+	}
+	opts := defaultInstanceOpts(netId, sgName, keyName)
+	server, err := defaultController.CreateInstance(ctx, opts)
+	return server.Id, err
 }
 
-func CreateQosPolicyHelper() string {
+func CreateQosPolicyHelper(ctx context.Context) (string, error) {
 	opts := defaultQosPolicyRequestOpts()
-	qosPolicy := defaultController.createQosPolicy(opts)
-	return qosPolicy.Id
+	qosPolicy, err := defaultController.createQosPolicy(ctx, opts)
+	return qosPolicy.Id, err
 }
 
-func CreateBandwidthLimitRuleHelper(qosPolicyId string) {
+func CreateBandwidthLimitRuleHelper(ctx context.Context, qosPolicyId string) error {
 	ingressOpts := defaultBandwidthLimitRuleIngressRequestOpts()
 	egressOpts := defaultBandwidthLimitRuleEgressRequestOpts()
-	defaultController.CreateBandwidthLimitRule(ingressOpts, qosPolicyId)
-	defaultController.CreateBandwidthLimitRule(egressOpts, qosPolicyId)
+	if _, err := defaultController.CreateBandwidthLimitRule(ctx, ingressOpts, qosPolicyId); err != nil {
+		return err
+	}
+	_, err := defaultController.CreateBandwidthLimitRule(ctx, egressOpts, qosPolicyId)
+	return err
 }
 
-func defaultNetworkOpts() entity.CreateUpdateOptions {
+func defaultNetworkOpts() *entity.CreateNetworkOpts {
 	return &entity.CreateNetworkOpts{Name: defaultName}
 }
 
-func defaultSubnetOpts(netId string) entity.CreateUpdateOptions {
+func defaultSubnetOpts(netId string) *entity.CreateSubnetOpts {
 	rand.Seed(time.Now().UnixNano())
 	randomNum := rand.Intn(200)
 	cidr := fmt.Sprintf("192.%d.%d.0/24", randomNum, randomNum)
@@ -114,7 +135,7 @@ func defaultSubnetOpts(netId string) entity.CreateUpdateOptions {
 	return subnetOpts
 }
 
-func defaultPortOpts(netId, subnetId string) entity.CreateUpdateOptions {
+func defaultPortOpts(netId, subnetId string) *entity.CreatePortOpts {
 	fixedIp1 := entity.FixedIP{SubnetId: subnetId}
 	fixedIp2 := entity.FixedIP{SubnetId: subnetId}
 	opts := &entity.CreatePortOpts{
@@ -124,90 +145,89 @@ func defaultPortOpts(netId, subnetId string) entity.CreateUpdateOptions {
 	return opts
 }
 
-func defaultSgOpts() entity.CreateUpdateOptions {
+func defaultSgOpts() *entity.CreateSecurityGroupOpts {
 	opts := &entity.CreateSecurityGroupOpts{Name: defaultName}
 	return opts
 }
 
-func defaultICMPIngressSgRuleOpts(sgId string) entity.CreateUpdateOptions {
+func defaultICMPIngressSgRuleOpts(sgId string) *entity.CreateSecurityRuleOpts {
 	ingress := &entity.CreateSecurityRuleOpts{
 		Direction: "ingress", EtherType: "IPv4",
 		Protocol: "icmp", SecGroupID: sgId}
 	return ingress
 }
 
-func defaultICMPEgressSgRuleOpts(sgId string) entity.CreateUpdateOptions {
+func defaultICMPEgressSgRuleOpts(sgId string) *entity.CreateSecurityRuleOpts {
 	egress := &entity.CreateSecurityRuleOpts{
 		Direction: "egress", EtherType: "IPv4",
 		Protocol: "icmp", SecGroupID: sgId}
 	return egress
 }
 
-func defaultSSHIngressSgRuleOpts(sgId string) entity.CreateUpdateOptions {
+func defaultSSHIngressSgRuleOpts(sgId string) *entity.CreateSecurityRuleOpts {
 	return &entity.CreateSecurityRuleOpts{
 		Direction: "ingress", EtherType: "IPv4", Protocol: "tcp",
 		SecGroupID: sgId, PortRangeMin: 22, PortRangeMax: 22}
 }
 
-func defaultSSHEgressSgRuleOpts(sgId string) entity.CreateUpdateOptions {
+func defaultSSHEgressSgRuleOpts(sgId string) *entity.CreateSecurityRuleOpts {
 	return &entity.CreateSecurityRuleOpts{
 		Direction: "egress", EtherType: "IPv4", Protocol: "tcp",
 		SecGroupID: sgId, PortRangeMin: 22, PortRangeMax: 22}
 }
 
-func defaultInstanceOpts(netId, sgName string) entity.CreateUpdateOptions {
+func defaultInstanceOpts(netId, sgName, keyName string) *entity.CreateInstanceOpts {
 	instanceOpts := &entity.CreateInstanceOpts{
-		FlavorRef:      configs.CONF.FlavorId,
-		ImageRef:       configs.CONF.ImageId,
+		FlavorRef:      defaultController.config().FlavorId,
+		ImageRef:       defaultController.config().ImageId,
 		Networks:       []entity.ServerNet{{UUID: netId}},
-		AdminPass:      "Wang.123",
 		SecurityGroups: []entity.ServerSg{{Name: sgName}},
 		Name:           defaultName,
-		UserData: "Q29udGVudC1UeXBlOiBtdWx0aXBhcnQvbWl4ZWQ7IGJvdW5kYXJ5PSI9PT09PT09PT09PT09PT0yMzA5OTg0MDU5NzQzNzYyNDc1PT0iIApNSU1FLVZlcnNpb246IDEuMAoKLS09PT09PT09PT09PT09PT0yMzA5OTg0MDU5NzQzNzYyNDc1PT0KQ29udGVudC1UeXBlOiB0ZXh0L2Nsb3VkLWNvbmZpZzsgY2hhcnNldD0idXMtYXNjaWkiIApNSU1FLVZlcnNpb246IDEuMApDb250ZW50LVRyYW5zZmVyLUVuY29kaW5nOiA3Yml0CkNvbnRlbnQtRGlzcG9zaXRpb246IGF0dGFjaG1lbnQ7IGZpbGVuYW1lPSJzc2gtcHdhdXRoLXNjcmlwdC50eHQiIAoKI2Nsb3VkLWNvbmZpZwpkaXNhYmxlX3Jvb3Q6IGZhbHNlCnNzaF9wd2F1dGg6IHRydWUKcGFzc3dvcmQ6IFdhbmcuMTIzCgotLT09PT09PT09PT09PT09PTIzMDk5ODQwNTk3NDM3NjI0NzU9PQpDb250ZW50LVR5cGU6IHRleHQveC1zaGVsbHNjcmlwdDsgY2hhcnNldD0idXMtYXNjaWkiIApNSU1FLVZlcnNpb246IDEuMApDb250ZW50LVRyYW5zZmVyLUVuY29kaW5nOiA3Yml0CkNvbnRlbnQtRGlzcG9zaXRpb246IGF0dGFjaG1lbnQ7IGZpbGVuYW1lPSJwYXNzd2Qtc2NyaXB0LnR4dCIgCgojIS9iaW4vc2gKZWNobyAncm9vdDpXYW5nLjEyMycgfCBjaHBhc3N3ZAoKLS09PT09PT09PT09PT09PT0yMzA5OTg0MDU5NzQzNzYyNDc1PT0KQ29udGVudC1UeXBlOiB0ZXh0L3gtc2hlbGxzY3JpcHQ7IGNoYXJzZXQ9InVzLWFzY2lpIiAKTUlNRS1WZXJzaW9uOiAxLjAKQ29udGVudC1UcmFuc2Zlci1FbmNvZGluZzogN2JpdApDb250ZW50LURpc3Bvc2l0aW9uOiBhdHRhY2htZW50OyBmaWxlbmFtZT0iZW5hYmxlLWZzLWNvbGxlY3Rvci50eHQiIAoKIyEvYmluL3NoCnFlbXVfZmlsZT0iL2V0Yy9zeXNjb25maWcvcWVtdS1nYSIKaWYgWyAtZiAke3FlbXVfZmlsZX0gXTsgdGhlbgogICAgc2VkIC1pIC1yICJzL14jP0JMQUNLTElTVF9SUEM9LyNCTEFDS0xJU1RfUlBDPS8iICIke3FlbXVfZmlsZX0iCiAgICBoYXNfZ3FhPSQoc3lzdGVtY3RsIGxpc3QtdW5pdHMgLS1mdWxsIC1hbGwgLXQgc2VydmljZSAtLXBsYWluIHwgZ3JlcCAtbyBxZW11LWd1ZXN0LWFnZW50LnNlcnZpY2UpCiAgICBpZiBbWyAtbiAke2hhc19ncWF9IF1dOyB0aGVuCiAgICAgICAgc3lzdGVtY3RsIHJlc3RhcnQgcWVtdS1ndWVzdC1hZ2VudC5zZXJ2aWNlCiAgICBmaQpmaQoKLS09PT09PT09PT09PT09PT0yMzA5OTg0MDU5NzQzNzYyNDc1PT0tLQ==",
+		KeyName:        keyName,
 		BlockDeviceMappingV2: []entity.BlockDeviceMapping{{
-			BootIndex: 0, Uuid: configs.CONF.ImageId, SourceType: "image",
+			BootIndex: 0, Uuid: defaultController.config().ImageId, SourceType: "image",
 			DestinationType: "volume", VolumeSize: 20, DeleteOnTermination: true,
 		}},
 	}
 	return instanceOpts
 }
 
-func defaultRouterOpts() entity.CreateUpdateOptions {
+func defaultRouterOpts() *entity.CreateRouterOpts {
 	routerOpts := &entity.CreateRouterOpts{
 		Name: defaultName, Description: defaultName,
 	}
 	return routerOpts
 }
 
-func defaultRouterGatewayOpts(externalNetId string) entity.CreateUpdateOptions {
+func defaultRouterGatewayOpts(externalNetId string) *entity.UpdateRouterOpts {
 	updateRouterOpts := &entity.UpdateRouterOpts{
 		GatewayInfo: &entity.GatewayInfo{
 			NetworkID: externalNetId}}
 	return updateRouterOpts
 }
 
-func defaultRouterInterfaceOpts(routerId, subnetId string) entity.CreateUpdateOptions {
+func defaultRouterInterfaceOpts(routerId, subnetId string) *entity.AddRouterInterfaceOpts {
 	addInterfaceOpts := &entity.AddRouterInterfaceOpts{
 		SubnetID: subnetId, RouterId: routerId,
 	}
 	return addInterfaceOpts
 }
 
-func defaultQosPolicyRequestOpts() entity.CreateUpdateOptions {
+func defaultQosPolicyRequestOpts() *entity.CreateQosPolicyOpts {
 	opts := &entity.CreateQosPolicyOpts{
 		Name: defaultName,
 	}
 	return opts
 }
 
-func defaultBandwidthLimitRuleIngressRequestOpts() entity.CreateUpdateOptions {
+func defaultBandwidthLimitRuleIngressRequestOpts() *entity.CreateBandwidthLimitRuleOpts {
 	opts := &entity.CreateBandwidthLimitRuleOpts{
 		MaxKBps: 10240, MaxBurstKBps: 0, Direction: "ingress",
 	}
 	return opts
 }
 
-func defaultBandwidthLimitRuleEgressRequestOpts() entity.CreateUpdateOptions {
+func defaultBandwidthLimitRuleEgressRequestOpts() *entity.CreateBandwidthLimitRuleOpts {
 	opts := &entity.CreateBandwidthLimitRuleOpts{
 		MaxKBps: 20480, MaxBurstKBps: 0, Direction: "egress",
 	}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCreateInstanceHelperNeedsKeypair(t *testing.T) {
+	if defaultController.config().KeyName != "" {
+		t.Skip("the profile names a keypair")
+	}
+	_, err := CreateInstanceHelper(context.Background(), "net", "sg", "")
+	if err == nil || !strings.Contains(err.Error(), "no keypair") {
+		t.Errorf("CreateInstanceHelper() without a keypair = %v, want a missing keypair error", err)
+	}
+}
+
+func TestDefaultInstanceOptsHasNoPassword(t *testing.T) {
+	opts := defaultInstanceOpts("net", "sg", "key")
+	if opts.KeyName != "key" || opts.AdminPass != "" || opts.UserData != "" {
+		t.Errorf("defaultInstanceOpts() = key %q, admin pass %q, user data %q, want only the keypair",
+			opts.KeyName, opts.AdminPass, opts.UserData)
+	}
+}
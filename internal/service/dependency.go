@@ -16,18 +16,27 @@ var ResourceDependencies = map[string][]string{
 	consts.DSCP_MARKING_RULE: []string{consts.QOS_POLICY},
 	consts.MINIMUM_BANDWIDTH_RULE: []string{consts.QOS_POLICY},
 	consts.NETWORK: []string{consts.QOS_POLICY},
+	consts.RBACPOLICY: []string{consts.NETWORK, consts.QOS_POLICY, consts.SECURITYGROUP},
 	consts.SUBNET: []string{consts.NETWORK},
 	consts.PORT: []string{consts.SUBNET, consts.SECURITYGROUP, consts.QOS_POLICY},
 	consts.ROUTERINTERFACE: []string{consts.ROUTER, consts.PORT},
 	consts.ROUTERGATEWAY: []string{consts.ROUTER, consts.PORT},
 	consts.ROUTERROUTE: []string{consts.ROUTERINTERFACE, consts.ROUTERGATEWAY},
+	consts.IKEPOLICY: []string{},
+	consts.IPSECPOLICY: []string{},
+	consts.ENDPOINTGROUP: []string{consts.SUBNET},
+	consts.VPNSERVICE: []string{consts.ROUTERINTERFACE, consts.ROUTERGATEWAY},
+	consts.IPSECCONNECTION: []string{consts.VPNSERVICE, consts.ENDPOINTGROUP, consts.IKEPOLICY, consts.IPSECPOLICY},
 	consts.SNAPSHOT: []string{consts.VOLUME},
 	consts.SERVER: []string{consts.SECURITYGROUP, consts.PORT, consts.VOLUME},
 	consts.FLOATINGIP: []string{consts.SERVER, consts.ROUTERGATEWAY, consts.ROUTERINTERFACE},
 	consts.PORTFORWARDING: []string{consts.FLOATINGIP},
+	consts.Snat: []string{consts.ROUTERGATEWAY, consts.ROUTERINTERFACE},
+	consts.Dnat: []string{consts.ROUTERGATEWAY, consts.FLOATINGIP, consts.PORT},
 	consts.FIREWALLRULE: []string{consts.PORT},
 	consts.FIREWALLPOLICY: []string{consts.FIREWALLRULE},
 	consts.FIREWALL: []string{consts.FIREWALLPOLICY, consts.ROUTER},
+	consts.FIREWALLGROUP: []string{consts.FIREWALLPOLICY, consts.ROUTERINTERFACE, consts.PORT},
 	consts.VpcConnection: []string{consts.ROUTERINTERFACE, consts.ROUTERGATEWAY, consts.FIREWALL},
 	consts.LOADBALANCER: []string{consts.SUBNET},
 	consts.LISTENER: []string{consts.LOADBALANCER},
@@ -48,18 +57,27 @@ var OrderResources = [...]string{
 	consts.DSCP_MARKING_RULE,
 	consts.MINIMUM_BANDWIDTH_RULE,
 	consts.NETWORK,
+	consts.RBACPOLICY,
 	consts.SUBNET,
 	consts.PORT,
 	consts.ROUTERINTERFACE,
 	consts.ROUTERGATEWAY,
 	consts.ROUTERROUTE,
+	consts.IKEPOLICY,
+	consts.IPSECPOLICY,
+	consts.ENDPOINTGROUP,
+	consts.VPNSERVICE,
+	consts.IPSECCONNECTION,
 	consts.SNAPSHOT,
 	consts.SERVER,
 	consts.FLOATINGIP,
 	consts.PORTFORWARDING,
+	consts.Snat,
+	consts.Dnat,
 	consts.FIREWALLRULE,
 	consts.FIREWALLPOLICY,
 	consts.FIREWALL,
+	consts.FIREWALLGROUP,
 	consts.VpcConnection,
 	consts.LOADBALANCER,
 	consts.LISTENER,
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"go-openstackclient/consts"
+	"go-openstackclient/internal/client"
+	"go-openstackclient/internal/entity"
+	"go-openstackclient/utils"
+	"log"
+)
+
+// FWaaS v2 lives below fwaas/ and binds firewall groups to ports, the v1
+// extension below fw/ binds firewalls to routers. A cloud runs one of them,
+// the Cleaner tries both and skips the one that is not loaded.
+
+type (
+	FirewallGroupClient    = ResourceClient[entity.FirewallGroup, *entity.CreateFirewallGroupOpts, *entity.UpdateFirewallGroupOpts]
+	FirewallPolicyClient   = ResourceClient[entity.FirewallPolicy, *entity.CreateFirewallPolicyOpts, *entity.UpdateFirewallPolicyOpts]
+	FirewallRuleClient     = ResourceClient[entity.FirewallRule, *entity.CreateFirewallRuleOpts, *entity.UpdateFirewallRuleOpts]
+	FirewallV1Client       = ResourceClient[entity.Firewall, *entity.CreateFirewallOpts, *entity.UpdateFirewallOpts]
+	FirewallPolicyV1Client = ResourceClient[entity.FirewallPolicyV1, *entity.CreateFirewallPolicyOpts, *entity.UpdateFirewallPolicyOpts]
+	FirewallRuleV1Client   = ResourceClient[entity.FirewallRuleV1, *entity.CreateFirewallRuleOpts, *entity.UpdateFirewallRuleOpts]
+)
+
+func (s *Controller) FirewallGroups() *FirewallGroupClient {
+	return NewResourceClient[entity.FirewallGroup, *entity.CreateFirewallGroupOpts, *entity.UpdateFirewallGroupOpts](s, ResourceSpec{
+		Resource: consts.FIREWALLGROUP, Collection: "fwaas/" + consts.FIREWALLGROUPS,
+		Key: consts.FIREWALLGROUP, PluralKey: consts.FIREWALLGROUPS, Paging: LinksPaging})
+}
+
+func (s *Controller) FirewallPolicies() *FirewallPolicyClient {
+	return NewResourceClient[entity.FirewallPolicy, *entity.CreateFirewallPolicyOpts, *entity.UpdateFirewallPolicyOpts](s, ResourceSpec{
+		Resource: consts.FIREWALLPOLICY, Collection: "fwaas/" + consts.FIREWALLPOLICIES,
+		Key: consts.FIREWALLPOLICY, PluralKey: consts.FIREWALLPOLICIES, Paging: LinksPaging})
+}
+
+func (s *Controller) FirewallRules() *FirewallRuleClient {
+	return NewResourceClient[entity.FirewallRule, *entity.CreateFirewallRuleOpts, *entity.UpdateFirewallRuleOpts](s, ResourceSpec{
+		Resource: consts.FIREWALLRULE, Collection: "fwaas/" + consts.FIREWALLRULES,
+		Key: consts.FIREWALLRULE, PluralKey: consts.FIREWALLRULES, Paging: LinksPaging})
+}
+
+func (s *Controller) FirewallsV1() *FirewallV1Client {
+	return NewResourceClient[entity.Firewall, *entity.CreateFirewallOpts, *entity.UpdateFirewallOpts](s, ResourceSpec{
+		Resource: consts.FIREWALL, Collection: "fw/" + consts.FIREWALLS,
+		Key: consts.FIREWALL, PluralKey: consts.FIREWALLS, Paging: LinksPaging})
+}
+
+func (s *Controller) FirewallPoliciesV1() *FirewallPolicyV1Client {
+	return NewResourceClient[entity.FirewallPolicyV1, *entity.CreateFirewallPolicyOpts, *entity.UpdateFirewallPolicyOpts](s, ResourceSpec{
+		Resource: consts.FIREWALLPOLICY, Collection: "fw/" + consts.FIREWALLPOLICIES,
+		Key: consts.FIREWALLPOLICY, PluralKey: consts.FIREWALLPOLICIES, Paging: LinksPaging})
+}
+
+func (s *Controller) FirewallRulesV1() *FirewallRuleV1Client {
+	return NewResourceClient[entity.FirewallRuleV1, *entity.CreateFirewallRuleOpts, *entity.UpdateFirewallRuleOpts](s, ResourceSpec{
+		Resource: consts.FIREWALLRULE, Collection: "fw/" + consts.FIREWALLRULES,
+		Key: consts.FIREWALLRULE, PluralKey: consts.FIREWALLRULES, Paging: LinksPaging})
+}
+
+// InsertFirewallRule adds a rule to a v2 policy at the position of opts and
+// returns the policy with its new rule order.
+func (s *Controller) InsertFirewallRule(ctx context.Context, policyId string, opts *entity.FirewallRuleInsertOpts) (entity.FirewallPolicy, error) {
+	var policy entity.FirewallPolicy
+	err := s.FirewallPolicies().call(ctx, UPDATE, policyId, "insert_rule", opts, &policy)
+	if err == nil {
+		log.Println("==============Insert firewall rule success", opts.FirewallRuleID, policyId)
+	}
+	return policy, err
+}
+
+func (s *Controller) RemoveFirewallRule(ctx context.Context, policyId, ruleId string) (entity.FirewallPolicy, error) {
+	var policy entity.FirewallPolicy
+	err := s.FirewallPolicies().call(ctx, UPDATE, policyId, "remove_rule",
+		&entity.FirewallRuleRemoveOpts{FirewallRuleID: ruleId}, &policy)
+	if err == nil {
+		log.Println("==============Remove firewall rule success", ruleId, policyId)
+	}
+	return policy, err
+}
+
+func (s *Controller) InsertFirewallRuleV1(ctx context.Context, policyId string, opts *entity.FirewallRuleInsertOpts) (entity.FirewallPolicyV1, error) {
+	var policy entity.FirewallPolicyV1
+	err := s.FirewallPoliciesV1().call(ctx, UPDATE, policyId, "insert_rule", opts, &policy)
+	if err == nil {
+		log.Println("==============Insert firewall rule success", opts.FirewallRuleID, policyId)
+	}
+	return policy, err
+}
+
+func (s *Controller) RemoveFirewallRuleV1(ctx context.Context, policyId, ruleId string) (entity.FirewallPolicyV1, error) {
+	var policy entity.FirewallPolicyV1
+	err := s.FirewallPoliciesV1().call(ctx, UPDATE, policyId, "remove_rule",
+		&entity.FirewallRuleRemoveOpts{FirewallRuleID: ruleId}, &policy)
+	if err == nil {
+		log.Println("==============Remove firewall rule success", ruleId, policyId)
+	}
+	return policy, err
+}
+
+// SetFirewallGroupPorts binds exactly ports to the group, none when ports is
+// empty.
+func (s *Controller) SetFirewallGroupPorts(ctx context.Context, groupId string, ports []string) (entity.FirewallGroup, error) {
+	if ports == nil {
+		ports = []string{}
+	}
+	return s.FirewallGroups().Update(ctx, groupId, &entity.UpdateFirewallGroupOpts{Ports: &ports})
+}
+
+// AddFirewallGroupPorts binds ports to the group next to the ports it
+// already protects.
+func (s *Controller) AddFirewallGroupPorts(ctx context.Context, groupId string, ports ...string) (entity.FirewallGroup, error) {
+	group, err := s.FirewallGroups().Get(ctx, groupId)
+	if err != nil {
+		return group, err
+	}
+	bound := make(map[string]struct{}, len(group.Ports))
+	for _, port := range group.Ports {
+		bound[port] = struct{}{}
+	}
+	newPorts := append([]string{}, group.Ports...)
+	for _, port := range ports {
+		if _, ok := bound[port]; !ok {
+			bound[port] = struct{}{}
+			newPorts = append(newPorts, port)
+		}
+	}
+	if len(newPorts) == len(group.Ports) {
+		return group, nil
+	}
+	return s.SetFirewallGroupPorts(ctx, groupId, newPorts)
+}
+
+func (s *Controller) RemoveFirewallGroupPorts(ctx context.Context, groupId string, ports ...string) (entity.FirewallGroup, error) {
+	group, err := s.FirewallGroups().Get(ctx, groupId)
+	if err != nil {
+		return group, err
+	}
+	removed := make(map[string]struct{}, len(ports))
+	for _, port := range ports {
+		removed[port] = struct{}{}
+	}
+	newPorts := make([]string, 0, len(group.Ports))
+	for _, port := range group.Ports {
+		if _, ok := removed[port]; !ok {
+			newPorts = append(newPorts, port)
+		}
+	}
+	if len(newPorts) == len(group.Ports) {
+		return group, nil
+	}
+	return s.SetFirewallGroupPorts(ctx, groupId, newPorts)
+}
+
+// deleteFirewallGroup unbinds the ports of the group first, neutron refuses
+// to delete a group still protecting ports.
+func (s *Controller) deleteFirewallGroup(ctx context.Context, groupId string) error {
+	group, err := s.FirewallGroups().Get(ctx, groupId)
+	if err != nil {
+		return err
+	}
+	if len(group.Ports) > 0 {
+		if _, err = s.SetFirewallGroupPorts(ctx, groupId, nil); err != nil {
+			return err
+		}
+	}
+	return s.FirewallGroups().Delete(ctx, groupId)
+}
+
+// firewallIDs lists the project ids of a firewall collection, none when the
+// FWaaS version serving it is not loaded.
+func firewallIDs(ctx context.Context, list func(ctx context.Context) ([]string, error)) ([]string, error) {
+	ids, err := list(ctx)
+	if client.IsNotFound(err) {
+		return nil, nil
+	}
+	return ids, err
+}
+
+// deleteFirewallResources deletes the project's items of a v2 collection and
+// of its v1 counterpart into the delete channel of resourceType.
+func (s *Controller) deleteFirewallResources(ctx context.Context, resourceType string, v2, v1 func(ctx context.Context) ([]string, error), delV2, delV1 func(ctx context.Context, id string) error) error {
+	v2Ids, err := firewallIDs(ctx, v2)
+	if err != nil {
+		return err
+	}
+	v1Ids, err := firewallIDs(ctx, v1)
+	if err != nil {
+		return err
+	}
+	isV1 := make(map[string]struct{}, len(v1Ids))
+	for _, id := range v1Ids {
+		isV1[id] = struct{}{}
+	}
+	err = s.deleteConcurrently(ctx, resourceType, resourceType+"_id", append(v2Ids, v1Ids...), func(ctx context.Context, id string) error {
+		if _, ok := isV1[id]; ok {
+			return delV1(ctx, id)
+		}
+		return delV2(ctx, id)
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("%s were deleted completely", utils.Pluralize(resourceType))
+	return nil
+}
+
+// The Delete<Resources> methods below are called by the Cleaner.
+
+func (s *Controller) DeleteFirewalls(ctx context.Context) error {
+	ids, err := firewallIDs(ctx, s.FirewallsV1().ProjectIDs)
+	if err != nil {
+		return err
+	}
+	return s.deleteConcurrently(ctx, consts.FIREWALL, consts.FIREWALL+"_id", ids, s.FirewallsV1().Delete)
+}
+
+// DeleteFirewallGroups leaves the default group alone, neutron recreates it
+// for the project and deletes it with the project.
+func (s *Controller) DeleteFirewallGroups(ctx context.Context) error {
+	query, err := s.projectFilter(ctx)
+	if err != nil {
+		return err
+	}
+	groups, err := s.FirewallGroups().List(ctx, query)
+	if err != nil && !client.IsNotFound(err) {
+		return err
+	}
+	ids := make([]string, 0, len(groups))
+	for _, group := range groups {
+		if group.Name != "default" {
+			ids = append(ids, group.Id)
+		}
+	}
+	return s.deleteConcurrently(ctx, consts.FIREWALLGROUP, consts.FIREWALLGROUP+"_id", ids, s.deleteFirewallGroup)
+}
+
+func (s *Controller) DeleteFirewallPolicies(ctx context.Context) error {
+	return s.deleteFirewallResources(ctx, consts.FIREWALLPOLICY,
+		s.FirewallPolicies().ProjectIDs, s.FirewallPoliciesV1().ProjectIDs,
+		s.FirewallPolicies().Delete, s.FirewallPoliciesV1().Delete)
+}
+
+func (s *Controller) DeleteFirewallRules(ctx context.Context) error {
+	return s.deleteFirewallResources(ctx, consts.FIREWALLRULE,
+		s.FirewallRules().ProjectIDs, s.FirewallRulesV1().ProjectIDs,
+		s.FirewallRules().Delete, s.FirewallRulesV1().Delete)
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-openstackclient/consts"
+	"go-openstackclient/internal/client"
+	"go-openstackclient/internal/entity"
+	"log"
+	"net/url"
+)
+
+type FlavorClient = ResourceClient[entity.Flavor, *entity.CreateFlavorOpts, *entity.UpdateFlavorOpts]
+
+// Flavors are created, updated and deleted by admins only. Update needs
+// microversion 2.55 and only changes the description.
+func (s *Controller) Flavors() *FlavorClient {
+	return NewResourceClient[entity.Flavor, *entity.CreateFlavorOpts, *entity.UpdateFlavorOpts](s, ResourceSpec{
+		Resource: consts.FLAVOR, Collection: consts.FLAVORS,
+		Key: consts.FLAVOR, PluralKey: consts.FLAVORS, Paging: MarkerPaging})
+}
+
+func (s *Controller) CreateFlavor(ctx context.Context, opts *entity.CreateFlavorOpts) (entity.Flavor, error) {
+	flavor, err := s.Flavors().Create(ctx, opts)
+	if err == nil {
+		log.Println("==============Create flavor success", flavor.Id)
+	}
+	return flavor, err
+}
+
+func (s *Controller) GetFlavor(ctx context.Context, flavorId string) (entity.Flavor, error) {
+	return s.Flavors().Get(ctx, flavorId)
+}
+
+func (s *Controller) UpdateFlavor(ctx context.Context, flavorId, description string) (entity.Flavor, error) {
+	return s.Flavors().Update(ctx, flavorId, &entity.UpdateFlavorOpts{Description: description})
+}
+
+func (s *Controller) DeleteFlavor(ctx context.Context, flavorId string) error {
+	err := s.Flavors().Delete(ctx, flavorId)
+	if err == nil {
+		log.Println("==============Delete flavor success", flavorId)
+	}
+	return err
+}
+
+// ListFlavors lists the flavors in detail, admins see private flavors
+// too with is_public=None.
+func (s *Controller) ListFlavors(ctx context.Context, query string, opts ...ListOption) (entity.Flavors, error) {
+	var flavors entity.Flavors
+	if err := s.listAll(ctx, s.newPager(&ExtraOption{
+		Resource: consts.FLAVOR, ResourceLocation: fmt.Sprintf("%s/detail", consts.FLAVORS),
+		ResourceSuffix: query}, consts.FLAVORS, MarkerPaging, opts), &flavors); err != nil {
+		return flavors, err
+	}
+	log.Println("==============List flavors success, there had", flavors.Count)
+	return flavors, nil
+}
+
+// FindFlavor returns the flavor named name, nova can't filter the list by
+// name.
+func (s *Controller) FindFlavor(ctx context.Context, name string) (entity.Flavor, error) {
+	flavors, err := s.ListFlavors(ctx, "is_public=None")
+	if err != nil {
+		return entity.Flavor{}, err
+	}
+	found := make([]entity.Flavor, 0, 1)
+	for _, flavor := range flavors.Fs {
+		if flavor.Name == name {
+			found = append(found, flavor)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return entity.Flavor{}, fmt.Errorf("%s %q: %w", consts.FLAVOR, name, ErrNotFound)
+	case 1:
+		return found[0], nil
+	default:
+		return entity.Flavor{}, fmt.Errorf("found %d %s named %q, use the id instead", len(found), consts.FLAVORS, name)
+	}
+}
+
+func (s *Controller) GetFlavorExtraSpecs(ctx context.Context, flavorId string) (map[string]string, error) {
+	var specs entity.ExtraSpecsOpts
+	err := s.Flavors().call(ctx, GET, flavorId, "os-extra_specs", nil, &specs)
+	return specs.ExtraSpecs, err
+}
+
+// SetFlavorExtraSpecs adds specs to the flavor's extra specs, replacing the
+// values of the keys it already has.
+func (s *Controller) SetFlavorExtraSpecs(ctx context.Context, flavorId string, specs map[string]string) (map[string]string, error) {
+	var result entity.ExtraSpecsOpts
+	err := s.Flavors().call(ctx, CREATE, flavorId, "os-extra_specs", &entity.ExtraSpecsOpts{ExtraSpecs: specs}, &result)
+	if err == nil {
+		log.Println("==============Set flavor extra specs success", flavorId)
+	}
+	return result.ExtraSpecs, err
+}
+
+func (s *Controller) DeleteFlavorExtraSpec(ctx context.Context, flavorId, key string) error {
+	err := s.Flavors().call(ctx, DELETE, flavorId, "os-extra_specs/"+url.PathEscape(key), nil, nil)
+	if err == nil {
+		log.Println("==============Delete flavor extra spec success", flavorId, key)
+	}
+	return err
+}
+
+// ListFlavorAccess lists the projects allowed to use a private flavor, nova
+// refuses it for public ones.
+func (s *Controller) ListFlavorAccess(ctx context.Context, flavorId string) ([]entity.FlavorAccess, error) {
+	var accesses entity.FlavorAccesses
+	err := s.Flavors().call(ctx, GET, flavorId, "os-flavor-access", nil, &accesses)
+	return accesses.FlavorAccess, err
+}
+
+// AddFlavorAccess allows the project named projectName to use the private
+// flavor.
+func (s *Controller) AddFlavorAccess(ctx context.Context, flavorId, projectName string) ([]entity.FlavorAccess, error) {
+	return s.flavorAccessAction(ctx, flavorId, projectName, "addTenantAccess")
+}
+
+func (s *Controller) RemoveFlavorAccess(ctx context.Context, flavorId, projectName string) ([]entity.FlavorAccess, error) {
+	return s.flavorAccessAction(ctx, flavorId, projectName, "removeTenantAccess")
+}
+
+func (s *Controller) flavorAccessAction(ctx context.Context, flavorId, projectName, action string) ([]entity.FlavorAccess, error) {
+	projectId, err := s.projectIdByName(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+	var accesses entity.FlavorAccesses
+	if err = s.Flavors().call(ctx, CREATE, flavorId, "action",
+		&entity.FlavorAccessOpts{Action: action, Tenant: projectId}, &accesses); err != nil {
+		return nil, err
+	}
+	log.Printf("==============%s success %s %s", action, flavorId, projectName)
+	return accesses.FlavorAccess, nil
+}
+
+// flavorIdByName resolves the flavor named name, flavor ids are free form
+// so the id of an existing flavor is returned as is.
+func (s *Controller) flavorIdByName(ctx context.Context, name string) (string, error) {
+	flavor, err := s.GetFlavor(ctx, url.PathEscape(name))
+	if err == nil {
+		return flavor.Id, nil
+	}
+	if !client.IsNotFound(err) {
+		return "", err
+	}
+	flavor, err = s.FindFlavor(ctx, name)
+	return flavor.Id, err
+}
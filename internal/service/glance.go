@@ -17,12 +17,14 @@ var supportedGlanceResourceTypes = map[string]struct{}{
 type Glance struct {
 	client          client.Client
 	httpPrefix      string
+	catalog         *Catalog
 }
 
-func newGlance() *Glance {
+func newGlance(conf *configs.Openstack, catalog *Catalog, opts ...client.ClientOption) *Glance {
 	return &Glance{
-		client: client.NewClient(),
-		httpPrefix: fmt.Sprintf("http://%s:%d/v2", configs.CONF.Host, consts.GlancePort),
+		client: client.NewClient(opts...),
+		httpPrefix: fmt.Sprintf("http://%s:%d/v2", conf.Host, consts.GlancePort),
+		catalog: catalog,
 	}
 }
 
@@ -30,8 +32,13 @@ func (g *Glance) SupportedResources() map[string]struct{} {
 	return supportedGlanceResourceTypes
 }
 
+// HttpPrefix resolves the endpoint from the keystone catalog, falling back
+// to the port-based url on the configured host.
 func (g *Glance) HttpPrefix() string {
-	return fmt.Sprintf("http://%s:%d/v2", configs.CONF.Host, consts.GlancePort)
+	if url, ok := g.catalog.URL("v2", consts.ImageService); ok {
+		return url
+	}
+	return g.httpPrefix
 }
 
 func (g *Glance) Client() client.Client {
@@ -41,11 +48,12 @@ func (g *Glance) Client() client.Client {
 	return g.client
 }
 
-func (g *Glance) Call(req client.Request) *fasthttp.Response {
+func (g *Glance) Call(ctx context.Context, req client.Request) (*fasthttp.Response, error) {
 	resp := fasthttp.AcquireResponse()
-	err := g.client.Call(context.Background(), req, resp)
+	err := g.client.Call(ctx, req, resp)
 	if err != nil {
-		panic(err)
+		fasthttp.ReleaseResponse(resp)
+		return nil, client.WithService(err, consts.GLANCE)
 	}
-	return resp
+	return resp, nil
 }
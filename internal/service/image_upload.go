@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/valyala/fasthttp"
+	"go-openstackclient/consts"
+	"go-openstackclient/internal/client"
+	"go-openstackclient/internal/entity"
+	"log"
+)
+
+// imageFailedStatuses are the glance statuses an image being uploaded never
+// leaves for active.
+var imageFailedStatuses = map[string]struct{}{
+	"killed": {}, "deleted": {}, "pending_delete": {}, "deactivated": {},
+}
+
+// SnapshotServer snapshots the server into the image name, metadata becomes
+// image properties, and returns the image id once it is active.
+func (s *Controller) SnapshotServer(ctx context.Context, serverId, name string, metadata map[string]string) (string, error) {
+	body, err := s.serverAction(ctx, serverId, &entity.CreateServerImageOpts{Name: name, Metadata: metadata})
+	if err != nil {
+		return "", err
+	}
+	var snapshot entity.ImageSnapshot
+	if err = json.Unmarshal(body, &snapshot); err != nil {
+		return "", err
+	}
+	if err = s.waitImageActive(ctx, snapshot.ImageId); err != nil {
+		return snapshot.ImageId, err
+	}
+	log.Println("==============Snapshot server success", serverId, snapshot.ImageId)
+	return snapshot.ImageId, nil
+}
+
+// UploadVolumeToImage uploads the volume to a new image and returns its id
+// once it is active.
+func (s *Controller) UploadVolumeToImage(ctx context.Context, volumeId string, opts *entity.UploadVolumeToImageOpts) (string, error) {
+	location, err := s.projectPath(ctx, "volumes/%s/action", volumeId)
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.Do(ctx, RequestOption{
+		Action: CREATE,
+		Resource: consts.VOLUME,
+		ResourceLocation: location,
+		Body: opts,
+		Headers: make(map[string]string),
+	})
+	if err != nil {
+		return "", err
+	}
+	defer fasthttp.ReleaseResponse(resp)
+
+	var upload entity.VolumeToImage
+	if err = json.Unmarshal(resp.Body(), &upload); err != nil {
+		return "", err
+	}
+	imageId := upload.OsVolumeUploadImage.ImageId
+	if err = s.waitImageActive(ctx, imageId); err != nil {
+		return imageId, err
+	}
+	log.Println("==============Upload volume to image success", volumeId, imageId)
+	return imageId, nil
+}
+
+// waitImageActive waits for the image to be active, failing when glance
+// kills it or the uploader deletes it after an error.
+func (s *Controller) waitImageActive(ctx context.Context, imageId string) error {
+	if imageId == "" {
+		return fmt.Errorf("no image id in the response")
+	}
+	return waitFor(ctx, "image "+imageId+" to become active", consts.IntervalTime, consts.ImageTimeout, func() (bool, error) {
+		image, err := s.GetImage(ctx, imageId)
+		if client.IsNotFound(err) {
+			return false, fmt.Errorf("image %s was deleted before becoming active", imageId)
+		}
+		if err != nil {
+			return false, err
+		}
+		if _, ok := imageFailedStatuses[image.Status]; ok {
+			return false, fmt.Errorf("image %s went to %s", imageId, image.Status)
+		}
+		return image.Status == "active", nil
+	})
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"go-openstackclient/consts"
+	"go-openstackclient/internal/entity"
+	"log"
+	"net/url"
+)
+
+type KeypairClient = ResourceClient[entity.Keypair, *entity.CreateKeypairOpts, entity.CreateUpdateOptions]
+
+// Keypairs are the keypairs of the user the token belongs to, nova names
+// them by name instead of id.
+func (s *Controller) Keypairs() *KeypairClient {
+	return NewResourceClient[entity.Keypair, *entity.CreateKeypairOpts, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.KEYPAIR, Collection: consts.OSKEYPAIRS,
+		Key: consts.KEYPAIR, PluralKey: consts.KEYPAIRS, Paging: MarkerPaging})
+}
+
+// CreateKeypair lets nova generate the keypair, the private key is only in
+// the returned keypair. Nova dropped the generation in microversion 2.92,
+// GenerateKeypair doesn't depend on it.
+func (s *Controller) CreateKeypair(ctx context.Context, name, keyType string) (entity.Keypair, error) {
+	keypair, err := s.Keypairs().Create(ctx, &entity.CreateKeypairOpts{Name: name, Type: keyType})
+	if err == nil {
+		log.Println("==============Create keypair success", name)
+	}
+	return keypair, err
+}
+
+// ImportKeypair uploads publicKey, an OpenSSH authorized_keys line.
+func (s *Controller) ImportKeypair(ctx context.Context, name, publicKey string) (entity.Keypair, error) {
+	keypair, err := s.Keypairs().Create(ctx, &entity.CreateKeypairOpts{Name: name, PublicKey: publicKey})
+	if err == nil {
+		log.Println("==============Import keypair success", name)
+	}
+	return keypair, err
+}
+
+// GenerateKeypair generates an ed25519 key locally and imports its public
+// half, the returned keypair carries the OpenSSH private key.
+func (s *Controller) GenerateKeypair(ctx context.Context, name string) (entity.Keypair, error) {
+	publicKey, privateKey, err := GenerateEd25519Key(name)
+	if err != nil {
+		return entity.Keypair{}, err
+	}
+	keypair, err := s.ImportKeypair(ctx, name, publicKey)
+	keypair.PrivateKey = privateKey
+	return keypair, err
+}
+
+func (s *Controller) GetKeypair(ctx context.Context, name string) (entity.Keypair, error) {
+	return s.Keypairs().Get(ctx, url.PathEscape(name))
+}
+
+func (s *Controller) ListKeypairs(ctx context.Context) ([]entity.Keypair, error) {
+	var keypairs entity.Keypairs
+	if err := s.listAll(ctx, s.newPager(&ExtraOption{
+		Resource: consts.KEYPAIR, ResourceLocation: consts.OSKEYPAIRS},
+		consts.KEYPAIRS, MarkerPaging, nil), &keypairs); err != nil {
+		return nil, err
+	}
+	result := make([]entity.Keypair, 0, len(keypairs.Ks))
+	for _, keypair := range keypairs.Ks {
+		result = append(result, keypair.Keypair)
+	}
+	log.Println("==============List keypairs success, there had", len(result))
+	return result, nil
+}
+
+func (s *Controller) DeleteKeypair(ctx context.Context, name string) error {
+	err := s.Keypairs().Delete(ctx, url.PathEscape(name))
+	if err == nil {
+		log.Println("==============Delete keypair success", name)
+	}
+	return err
+}
+
+// GenerateEd25519Key returns a new ed25519 key as an authorized_keys line
+// and an unencrypted OpenSSH private key, both ending with comment.
+func GenerateEd25519Key(comment string) (string, string, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	publicBlob := sshString(nil, []byte("ssh-ed25519"))
+	publicBlob = sshString(publicBlob, public)
+
+	var check [4]byte
+	if _, err = rand.Read(check[:]); err != nil {
+		return "", "", err
+	}
+	section := append(check[:], check[:]...)
+	section = sshString(section, []byte("ssh-ed25519"))
+	section = sshString(section, public)
+	section = sshString(section, private)
+	section = sshString(section, []byte(comment))
+	// the section is padded with 1, 2, 3... to the cipher block size, 8 for none
+	for i := 1; len(section)%8 != 0; i++ {
+		section = append(section, byte(i))
+	}
+
+	key := append([]byte("openssh-key-v1"), 0)
+	key = sshString(key, []byte("none"))
+	key = sshString(key, []byte("none"))
+	key = sshString(key, nil)
+	key = append(key, 0, 0, 0, 1)
+	key = sshString(key, publicBlob)
+	key = sshString(key, section)
+
+	publicKey := "ssh-ed25519 " + base64.StdEncoding.EncodeToString(publicBlob)
+	if comment != "" {
+		publicKey += " " + comment
+	}
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: key})
+	return publicKey, string(privateKey), nil
+}
+
+// sshString appends data to b as an SSH wire string, prefixed by its length.
+func sshString(b, data []byte) []byte {
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(data)))
+	b = append(b, length[:]...)
+	return append(b, data...)
+}
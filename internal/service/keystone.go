@@ -17,12 +17,16 @@ var supportedKeystoneResourceTypes = map[string]struct{}{
 type Keystone struct {
 	client          client.Client
 	httpPrefix      string
+	authURL         string
+	catalog         *Catalog
 }
 
-func newKeystone() *Keystone {
+func newKeystone(conf *configs.Openstack, catalog *Catalog, opts ...client.ClientOption) *Keystone {
 	return &Keystone{
-		client: client.NewClient(),
-		httpPrefix: fmt.Sprintf("http://%s:%d/v3/", configs.CONF.Host, consts.KeystonePort),
+		client: client.NewClient(opts...),
+		httpPrefix: fmt.Sprintf("http://%s:%d/v3", conf.Host, consts.KeystonePort),
+		authURL: conf.AuthURL,
+		catalog: catalog,
 	}
 }
 
@@ -30,8 +34,16 @@ func (k *Keystone) SupportedResources() map[string]struct{} {
 	return supportedKeystoneResourceTypes
 }
 
+// HttpPrefix resolves the endpoint from the keystone catalog, falling back
+// to the port-based url on the configured host.
 func (k *Keystone) HttpPrefix() string {
-	return fmt.Sprintf("http://%s:%d/v3", configs.CONF.Host, consts.KeystonePort)
+	if url, ok := k.catalog.URL("v3", consts.IdentityService); ok {
+		return url
+	}
+	if k.authURL != "" {
+		return normalizeEndpoint(k.authURL, "v3", "")
+	}
+	return k.httpPrefix
 }
 
 func (k *Keystone) Client() client.Client {
@@ -41,11 +53,12 @@ func (k *Keystone) Client() client.Client {
 	return k.client
 }
 
-func (k *Keystone) Call(req client.Request) *fasthttp.Response {
+func (k *Keystone) Call(ctx context.Context, req client.Request) (*fasthttp.Response, error) {
     resp := fasthttp.AcquireResponse()
-	err := k.client.Call(context.Background(), req, resp)
+	err := k.client.Call(ctx, req, resp)
 	if err != nil {
-		panic(err)
+		fasthttp.ReleaseResponse(resp)
+		return nil, client.WithService(err, consts.KEYSTONE)
 	}
-	return resp
+	return resp, nil
 }
\ No newline at end of file
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-openstackclient/consts"
+	"go-openstackclient/internal/entity"
+	"net"
+	"strings"
+)
+
+// SNAT and DNAT rules are resources of the vendor NAT extension backing the
+// SDN routers, they sit at the top of the neutron API next to the routers.
+
+type (
+	SnatClient = ResourceClient[entity.Snat, *entity.Snat, *entity.UpdateSnatOpts]
+	DnatClient = ResourceClient[entity.Dnat, *entity.Dnat, *entity.UpdateDnatOpts]
+)
+
+func (s *Controller) Snats() *SnatClient {
+	return NewResourceClient[entity.Snat, *entity.Snat, *entity.UpdateSnatOpts](s, ResourceSpec{
+		Resource: consts.Snat, Collection: consts.Snats,
+		Key: consts.Snat, PluralKey: consts.Snats, Paging: LinksPaging})
+}
+
+func (s *Controller) Dnats() *DnatClient {
+	return NewResourceClient[entity.Dnat, *entity.Dnat, *entity.UpdateDnatOpts](s, ResourceSpec{
+		Resource: consts.Dnat, Collection: consts.Dnats,
+		Key: consts.Dnat, PluralKey: consts.Dnats, Paging: LinksPaging})
+}
+
+// CreateSnat checks that the original CIDRs of opts lie in subnets attached
+// to its router before creating the rule.
+func (s *Controller) CreateSnat(ctx context.Context, opts *entity.Snat) (entity.Snat, error) {
+	if err := s.validateSnatCidrs(ctx, opts.RouterId, opts.OriginalCidrs); err != nil {
+		return entity.Snat{}, err
+	}
+	return s.Snats().Create(ctx, opts)
+}
+
+func (s *Controller) UpdateSnat(ctx context.Context, snatId string, opts *entity.UpdateSnatOpts) (entity.Snat, error) {
+	if len(opts.OriginalCidrs) > 0 {
+		snat, err := s.Snats().Get(ctx, snatId)
+		if err != nil {
+			return snat, err
+		}
+		if err = s.validateSnatCidrs(ctx, snat.RouterId, opts.OriginalCidrs); err != nil {
+			return snat, err
+		}
+	}
+	return s.Snats().Update(ctx, snatId, opts)
+}
+
+// CreateDnat checks the ports and protocol of opts and that no other DNAT
+// rule of its floating IP forwards the same port.
+func (s *Controller) CreateDnat(ctx context.Context, opts *entity.Dnat) (entity.Dnat, error) {
+	if err := validateDnatPorts(opts.Protocol, opts.FloatingIpPort, opts.FixedIpPort); err != nil {
+		return entity.Dnat{}, err
+	}
+	if err := s.checkDnatCollision(ctx, *opts); err != nil {
+		return entity.Dnat{}, err
+	}
+	return s.Dnats().Create(ctx, opts)
+}
+
+func (s *Controller) UpdateDnat(ctx context.Context, dnatId string, opts *entity.UpdateDnatOpts) (entity.Dnat, error) {
+	dnat, err := s.Dnats().Get(ctx, dnatId)
+	if err != nil {
+		return dnat, err
+	}
+	if opts.Protocol != "" {
+		dnat.Protocol = opts.Protocol
+	}
+	if opts.FloatingIpPort != 0 {
+		dnat.FloatingIpPort = opts.FloatingIpPort
+	}
+	if opts.FixedIpPort != 0 {
+		dnat.FixedIpPort = opts.FixedIpPort
+	}
+	if err = validateDnatPorts(dnat.Protocol, dnat.FloatingIpPort, dnat.FixedIpPort); err != nil {
+		return dnat, err
+	}
+	if opts.Protocol != "" || opts.FloatingIpPort != 0 {
+		if err = s.checkDnatCollision(ctx, dnat); err != nil {
+			return dnat, err
+		}
+	}
+	return s.Dnats().Update(ctx, dnatId, opts)
+}
+
+// routerSubnetCidrs returns the CIDRs of the subnets attached to the
+// interfaces of the router.
+func (s *Controller) routerSubnetCidrs(ctx context.Context, routerId string) ([]*net.IPNet, error) {
+	ports, err := s.Ports().List(ctx, "device_id="+routerId)
+	if err != nil {
+		return nil, err
+	}
+	cidrs := make([]*net.IPNet, 0)
+	for _, port := range ports {
+		if !strings.HasPrefix(port.DeviceOwner, "network:router_interface") {
+			continue
+		}
+		for _, fixedIp := range port.FixedIps {
+			subnet, err := s.Subnets().Get(ctx, fixedIp.SubnetId)
+			if err != nil {
+				return nil, err
+			}
+			_, cidr, err := net.ParseCIDR(subnet.Cidr)
+			if err != nil {
+				return nil, fmt.Errorf("subnet %s has an invalid cidr %q: %w", subnet.Id, subnet.Cidr, err)
+			}
+			cidrs = append(cidrs, cidr)
+		}
+	}
+	return cidrs, nil
+}
+
+func (s *Controller) validateSnatCidrs(ctx context.Context, routerId string, originalCidrs []string) error {
+	if len(originalCidrs) == 0 {
+		return fmt.Errorf("snat of router %s needs original cidrs", routerId)
+	}
+	attached, err := s.routerSubnetCidrs(ctx, routerId)
+	if err != nil {
+		return err
+	}
+	for _, original := range originalCidrs {
+		_, cidr, err := net.ParseCIDR(original)
+		if err != nil {
+			return fmt.Errorf("invalid original cidr %q: %w", original, err)
+		}
+		if !cidrWithin(cidr, attached) {
+			return fmt.Errorf("original cidr %s is not inside a subnet attached to router %s", original, routerId)
+		}
+	}
+	return nil
+}
+
+// cidrWithin reports whether cidr is contained in one of the networks.
+func cidrWithin(cidr *net.IPNet, networks []*net.IPNet) bool {
+	ones, bits := cidr.Mask.Size()
+	for _, network := range networks {
+		networkOnes, networkBits := network.Mask.Size()
+		if bits == networkBits && ones >= networkOnes && network.Contains(cidr.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateDnatPorts(protocol string, floatingIpPort, fixedIpPort int) error {
+	switch strings.ToLower(protocol) {
+	case "tcp", "udp":
+	default:
+		return fmt.Errorf("dnat protocol must be tcp or udp, got %q", protocol)
+	}
+	for _, port := range []int{floatingIpPort, fixedIpPort} {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("dnat port %d is out of range 1-65535", port)
+		}
+	}
+	return nil
+}
+
+// checkDnatCollision fails when another DNAT rule of the floating IP of dnat
+// already forwards its protocol and floating IP port.
+func (s *Controller) checkDnatCollision(ctx context.Context, dnat entity.Dnat) error {
+	var query string
+	switch {
+	case dnat.FloatingipId != "":
+		query = "floating_ip_id=" + dnat.FloatingipId
+	case dnat.FloatingIpAddress != "":
+		query = "floating_ip_address=" + dnat.FloatingIpAddress
+	default:
+		return fmt.Errorf("dnat needs a floating ip id or address")
+	}
+	dnats, err := s.Dnats().List(ctx, query)
+	if err != nil {
+		return err
+	}
+	for _, other := range dnats {
+		if other.Id == dnat.Id || !sameFloatingIP(other, dnat) {
+			continue
+		}
+		if strings.EqualFold(other.Protocol, dnat.Protocol) && other.FloatingIpPort == dnat.FloatingIpPort {
+			return fmt.Errorf("%s port %d of floating ip %s is already forwarded by dnat %s",
+				dnat.Protocol, dnat.FloatingIpPort, other.FloatingIpAddress, other.Id)
+		}
+	}
+	return nil
+}
+
+// sameFloatingIP compares by id when both rules carry one, the extension
+// may ignore the filter of the list.
+func sameFloatingIP(a, b entity.Dnat) bool {
+	if a.FloatingipId != "" && b.FloatingipId != "" {
+		return a.FloatingipId == b.FloatingipId
+	}
+	return a.FloatingIpAddress == b.FloatingIpAddress
+}
+
+// The Delete<Resources> methods below are called by the Cleaner, NAT rules
+// go before the router gateways and floating IPs they translate to.
+
+func (s *Controller) DeleteSnats(ctx context.Context) error {
+	return s.Snats().DeleteAll(ctx)
+}
+
+func (s *Controller) DeleteDnats(ctx context.Context) error {
+	return s.Dnats().DeleteAll(ctx)
+}
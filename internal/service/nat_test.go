@@ -0,0 +1,69 @@
+package service
+
+import (
+	"net"
+	"testing"
+)
+
+func TestCidrWithin(t *testing.T) {
+	var networks []*net.IPNet
+	for _, cidr := range []string{"10.0.0.0/16", "192.168.1.0/24", "fd00::/64"} {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		networks = append(networks, network)
+	}
+	tests := []struct {
+		cidr  string
+		want  bool
+	}{
+		{"10.0.0.0/16", true},
+		{"10.0.3.0/24", true},
+		{"10.0.3.7/32", true},
+		{"10.0.0.0/8", false},
+		{"10.1.0.0/24", false},
+		{"192.168.1.128/25", true},
+		{"192.168.0.0/23", false},
+		{"fd00::/80", true},
+		{"fd01::/80", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cidr, func(t *testing.T) {
+			_, cidr, err := net.ParseCIDR(tt.cidr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cidrWithin(cidr, networks); got != tt.want {
+				t.Errorf("cidrWithin(%s) = %v, want %v", tt.cidr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateDnatPorts(t *testing.T) {
+	tests := []struct {
+		name            string
+		protocol        string
+		floatingIpPort  int
+		fixedIpPort     int
+		err             bool
+	}{
+		{"tcp", "tcp", 8022, 22, false},
+		{"upper case udp", "UDP", 53, 53, false},
+		{"port bounds", "tcp", 1, 65535, false},
+		{"icmp", "icmp", 22, 22, true},
+		{"empty protocol", "", 22, 22, true},
+		{"zero floating ip port", "tcp", 0, 22, true},
+		{"fixed ip port too large", "tcp", 22, 65536, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDnatPorts(tt.protocol, tt.floatingIpPort, tt.fixedIpPort)
+			if (err != nil) != tt.err {
+				t.Errorf("validateDnatPorts(%q, %d, %d) = %v, want error %v",
+					tt.protocol, tt.floatingIpPort, tt.fixedIpPort, err, tt.err)
+			}
+		})
+	}
+}
@@ -19,18 +19,25 @@ var supportedNeutronResourceTypes = map[string]struct{}{
 	consts.ROUTERROUTE: struct{}{}, consts.FLOATINGIP: struct{}{},
 	consts.PORTFORWARDING: struct{}{}, consts.FIREWALLRULE: struct{}{},
 	consts.FIREWALLPOLICY: struct{}{}, consts.FIREWALL: struct{}{},
-	consts.VpcConnection: struct{}{},
+	consts.FIREWALLGROUP: struct{}{}, consts.Snat: struct{}{},
+	consts.Dnat: struct{}{}, consts.RBACPOLICY: struct{}{},
+	consts.QUOTA: struct{}{},
+	consts.VpcConnection: struct{}{}, consts.VPNSERVICE: struct{}{},
+	consts.ENDPOINTGROUP: struct{}{}, consts.IKEPOLICY: struct{}{},
+	consts.IPSECPOLICY: struct{}{}, consts.IPSECCONNECTION: struct{}{},
 }
 
 type Neutron struct {
 	client          client.Client
 	httpPrefix      string
+	catalog         *Catalog
 }
 
-func newNeutron() *Neutron {
+func newNeutron(conf *configs.Openstack, catalog *Catalog, opts ...client.ClientOption) *Neutron {
 	return &Neutron{
-		client: client.NewClient(),
-		httpPrefix: fmt.Sprintf("http://%s:%d/v2.0/", configs.CONF.Host, consts.NeutronPort),
+		client: client.NewClient(opts...),
+		httpPrefix: fmt.Sprintf("http://%s:%d/v2.0", conf.Host, consts.NeutronPort),
+		catalog: catalog,
 	}
 }
 
@@ -39,8 +46,13 @@ func (n *Neutron) SupportedResources() map[string]struct{} {
 }
 
 
+// HttpPrefix resolves the endpoint from the keystone catalog, falling back
+// to the port-based url on the configured host.
 func (n *Neutron) HttpPrefix() string {
-	return fmt.Sprintf("http://%s:%d/v2.0", configs.CONF.Host, consts.NeutronPort)
+	if url, ok := n.catalog.URL("v2.0", consts.NetworkService); ok {
+		return url
+	}
+	return n.httpPrefix
 }
 
 func (n *Neutron) Client() client.Client {
@@ -50,11 +62,12 @@ func (n *Neutron) Client() client.Client {
 	return n.client
 }
 
-func (n *Neutron) Call(req client.Request) *fasthttp.Response {
+func (n *Neutron) Call(ctx context.Context, req client.Request) (*fasthttp.Response, error) {
     resp := fasthttp.AcquireResponse()
-	err := n.client.Call(context.Background(), req, resp)
+	err := n.client.Call(ctx, req, resp)
 	if err != nil {
-		panic(err)
+		fasthttp.ReleaseResponse(resp)
+		return nil, client.WithService(err, consts.NEUTRON)
 	}
-	return resp
+	return resp, nil
 }
\ No newline at end of file
@@ -10,18 +10,23 @@ import (
 )
 
 var supportedNovaResourceTypes = map[string]struct{}{
-	consts.SERVER: struct{}{},
+	consts.SERVER: struct{}{}, consts.COMPUTEQUOTA: struct{}{},
+	consts.AGGREGATE: struct{}{}, consts.COMPUTESERVICE: struct{}{},
+	consts.HYPERVISOR: struct{}{}, consts.KEYPAIR: struct{}{},
+	consts.FLAVOR: struct{}{}, consts.SERVERGROUP: struct{}{},
 }
 
 type Nova struct {
 	client          client.Client
 	httpPrefix      string
+	catalog         *Catalog
 }
 
-func newNova() *Nova {
+func newNova(conf *configs.Openstack, catalog *Catalog, opts ...client.ClientOption) *Nova {
 	return &Nova{
-		client: client.NewClient(),
-		httpPrefix: fmt.Sprintf("http://%s:%d/v2.0/", configs.CONF.Host, consts.NovaPort),
+		client: client.NewClient(opts...),
+		httpPrefix: fmt.Sprintf("http://%s:%d/v2.1", conf.Host, consts.NovaPort),
+		catalog: catalog,
 	}
 }
 
@@ -30,8 +35,13 @@ func (n *Nova) SupportedResources() map[string]struct{} {
 }
 
 
+// HttpPrefix resolves the endpoint from the keystone catalog, falling back
+// to the port-based url on the configured host.
 func (n *Nova) HttpPrefix() string {
-	return fmt.Sprintf("http://%s:%d/v2.1", configs.CONF.Host, consts.NovaPort)
+	if url, ok := n.catalog.URL("v2.1", consts.ComputeService); ok {
+		return url
+	}
+	return n.httpPrefix
 }
 
 func (n *Nova) Client() client.Client {
@@ -41,12 +51,16 @@ func (n *Nova) Client() client.Client {
 	return n.client
 }
 
-func (n *Nova) Call(req client.Request) *fasthttp.Response {
+func (n *Nova) Call(ctx context.Context, req client.Request) (*fasthttp.Response, error) {
     resp := fasthttp.AcquireResponse()
-    req.Headers()["OpenStack-API-Version"] = "compute 2.74"
-	err := n.client.Call(context.Background(), req, resp)
+    // requests pinned to an older microversion keep their own header
+    if _, ok := req.Headers()[consts.OpenStackAPIVersion]; !ok {
+    	req.Headers()[consts.OpenStackAPIVersion] = "compute 2.74"
+	}
+	err := n.client.Call(ctx, req, resp)
 	if err != nil {
-		panic(err)
+		fasthttp.ReleaseResponse(resp)
+		return nil, client.WithService(err, consts.NOVA)
 	}
-	return resp
+	return resp, nil
 }
\ No newline at end of file
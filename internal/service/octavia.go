@@ -27,12 +27,14 @@ var supportedOctaviaResourceTypes = map[string]struct{}{
 type Octavia struct {
 	client          client.Client
 	httpPrefix      string
+	catalog         *Catalog
 }
 
-func newOctavia() *Octavia {
+func newOctavia(conf *configs.Openstack, catalog *Catalog, opts ...client.ClientOption) *Octavia {
 	return &Octavia{
-		client: client.NewClient(),
-		httpPrefix: fmt.Sprintf("http://%s:%d/v2.0/", configs.CONF.Host, consts.OctaviaPort),
+		client: client.NewClient(opts...),
+		httpPrefix: fmt.Sprintf("http://%s:%d/v2.0", conf.Host, consts.OctaviaPort),
+		catalog: catalog,
 	}
 }
 
@@ -40,8 +42,13 @@ func (o *Octavia) SupportedResources() map[string]struct{} {
 	return supportedOctaviaResourceTypes
 }
 
+// HttpPrefix resolves the endpoint from the keystone catalog, falling back
+// to the port-based url on the configured host.
 func (o *Octavia) HttpPrefix() string {
-	return fmt.Sprintf("http://%s:%d/v2.0/", configs.CONF.Host, consts.OctaviaPort)
+	if url, ok := o.catalog.URL("v2.0", consts.LoadBalancerService); ok {
+		return url
+	}
+	return o.httpPrefix
 }
 
 func (o *Octavia) Client() client.Client {
@@ -51,11 +58,12 @@ func (o *Octavia) Client() client.Client {
 	return o.client
 }
 
-func (o *Octavia) Call(req client.Request) *fasthttp.Response {
+func (o *Octavia) Call(ctx context.Context, req client.Request) (*fasthttp.Response, error) {
 	resp := fasthttp.AcquireResponse()
-	err := o.client.Call(context.Background(), req, resp)
+	err := o.client.Call(ctx, req, resp)
 	if err != nil {
-		panic(err)
+		fasthttp.ReleaseResponse(resp)
+		return nil, client.WithService(err, consts.OCTAVIA)
 	}
-	return resp
+	return resp, nil
 }
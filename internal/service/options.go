@@ -1,11 +1,8 @@
 package service
 
 import (
-	"fmt"
-	"go-openstackclient/consts"
 	"go-openstackclient/internal/client"
 	"go-openstackclient/internal/entity"
-	"strings"
 )
 
 type HookOpts struct {
@@ -29,119 +26,6 @@ type ExtraOption struct {
 }
 
 
-func constructNetworkRequestOpts(opts entity.CreateUpdateOptions, extraOption *ExtraOption) RequestOption {
-	netOpts := RequestOption{
-		Action: CREATE,
-		Resource: consts.NETWORK,
-		ResourceLocation: consts.NETWORKS,
-		RequestSuffix: "",
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-	return netOpts
-}
-
-func constructSubnetRequestOpts(opts entity.CreateUpdateOptions, extraOption *ExtraOption) RequestOption {
-	requestOpts := RequestOption{
-		Action: CREATE,
-		Resource: consts.SUBNET,
-		ResourceLocation: consts.SUBNETS,
-		RequestSuffix: "",
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-	return requestOpts
-}
-
-func constructPortRequestOpts(opts entity.CreateUpdateOptions, extraOption *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: CREATE,
-		Resource: consts.PORT,
-		ResourceLocation: consts.PORTS,
-		RequestSuffix: "",
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-func constructSgRequestOpts(opts entity.CreateUpdateOptions, extraOption *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: CREATE,
-		Resource: consts.SECURITYGROUP,
-		ResourceLocation: strings.Replace(consts.SECURITYGROUPS, "_", "-", 1),
-		RequestSuffix: "",
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-func constructRouterRequestOpts(opts entity.CreateUpdateOptions, extraOption *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: CREATE,
-		Resource: consts.ROUTER,
-		ResourceLocation: consts.ROUTERS,
-		RequestSuffix: "",
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-func constructRouterInterfaceRequestOpts(opts entity.CreateUpdateOptions, extraOption *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: UPDATE,
-		Resource: consts.ROUTER,
-		ResourceLocation: fmt.Sprintf("%s/%s/add_router_interface", consts.ROUTERS, opts.(*entity.AddRouterInterfaceOpts).RouterId),
-		RequestSuffix: "",
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-func constructSetRouterGatewayRequestOpts(opts entity.CreateUpdateOptions, extraOption *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: UPDATE,
-		Resource: consts.ROUTER,
-		ResourceLocation: fmt.Sprintf("%s/%s", consts.ROUTERS, extraOption.ParentID),
-		RequestSuffix: "",
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-func constructSgRuleRequestOpts(opts entity.CreateUpdateOptions, extraOpts *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: CREATE,
-		Resource: consts.SECURITYGROUPRULE,
-		ResourceLocation: strings.Replace(consts.SECURITYGROUPRULES, "_", "-", 1),
-		RequestSuffix: "",
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-func constructInstanceRequestOpts(opts entity.CreateUpdateOptions, extraOption *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: CREATE,
-		Resource: consts.SERVER,
-		ResourceLocation: consts.SERVERS,
-		RequestSuffix: "",
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-func constructUpdateRouterRequestOpts(opts entity.CreateUpdateOptions, extraOption *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: UPDATE,
-		Resource: consts.ROUTER,
-		ResourceLocation: fmt.Sprintf("%s/%s", consts.ROUTERS, extraOption.ParentID),
-		RequestSuffix: "",
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-
 func constructListRequestOpts(opts entity.CreateUpdateOptions, extraOpts *ExtraOption) RequestOption {
 	return RequestOption{
 		Action: GET,
@@ -149,156 +33,6 @@ func constructListRequestOpts(opts entity.CreateUpdateOptions, extraOpts *ExtraO
 		ResourceLocation: extraOpts.ResourceLocation,
 		RequestSuffix: extraOpts.ResourceSuffix,
 		Body: nil,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-func constructDeleteRequestOpts(opts entity.CreateUpdateOptions, extraOpts *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: DELETE,
-		Resource: extraOpts.Resource,
-		ResourceLocation: extraOpts.ResourceLocation,
-		RequestSuffix: extraOpts.ResourceSuffix,
-		Body: nil,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-func constructVolumeTypeRequestOpts(opts entity.CreateUpdateOptions, extraOpts *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: CREATE,
-		Resource: consts.VOLUMETYPE,
-		ResourceLocation: extraOpts.ResourceLocation,
-		RequestSuffix: extraOpts.ResourceSuffix,
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-func constructCreateVolumeRequestOpts(opts entity.CreateUpdateOptions, extraOpts *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: CREATE,
-		Resource: consts.VOLUME,
-		ResourceLocation: extraOpts.ResourceLocation,
-		RequestSuffix: extraOpts.ResourceSuffix,
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-func constructCreateSnapshotRequestOpts(opts entity.CreateUpdateOptions, extraOpts *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: CREATE,
-		Resource: consts.SNAPSHOT,
-		ResourceLocation: extraOpts.ResourceLocation,
-		RequestSuffix: extraOpts.ResourceSuffix,
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-func constructBandwidthLimitRuleRequestOpts(opts entity.CreateUpdateOptions, extraOption *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: CREATE,
-		Resource: consts.BANDWIDTH_LIMIT_RULE,
-		ResourceLocation: fmt.Sprintf("qos/policies/%s/bandwidth_limit_rules", extraOption.ParentID),
-		RequestSuffix: "",
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-func constructCreateLoadBalancerRequestOpts(opts entity.CreateUpdateOptions, extraOption *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: CREATE,
-		Resource: consts.LOADBALANCER,
-		ResourceLocation: extraOption.ResourceLocation,
-		RequestSuffix: "",
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-
-func constructCreateListenerRequestOpts(opts entity.CreateUpdateOptions, extraOption *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: CREATE,
-		Resource: consts.LISTENER,
-		ResourceLocation: extraOption.ResourceLocation,
-		RequestSuffix: "",
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-
-func constructCreatePoolRequestOpts(opts entity.CreateUpdateOptions, extraOption *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: CREATE,
-		Resource: consts.POOL,
-		ResourceLocation: extraOption.ResourceLocation,
-		RequestSuffix: "",
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-
-func constructCreatePoolMemberRequestOpts(opts entity.CreateUpdateOptions, extraOption *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: CREATE,
-		Resource: consts.MEMBER,
-		ResourceLocation: extraOption.ResourceLocation,
-		RequestSuffix: "",
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
+		Headers: make(map[string]string),
 	}
 }
-
-func constructCreateHealthMonitorRequestOpts(opts entity.CreateUpdateOptions, extraOption *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: CREATE,
-		Resource: consts.HEALTHMONITOR,
-		ResourceLocation: extraOption.ResourceLocation,
-		RequestSuffix: "",
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-
-func constructCreateL7PolicyRequestOpts(opts entity.CreateUpdateOptions, extraOption *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: CREATE,
-		Resource: consts.L7POLICY,
-		ResourceLocation: extraOption.ResourceLocation,
-		RequestSuffix: "",
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-func constructCreateL7RuleRequestOpts(opts entity.CreateUpdateOptions, extraOption *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: CREATE,
-		Resource: consts.L7RULE,
-		ResourceLocation: extraOption.ResourceLocation,
-		RequestSuffix: "",
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-
-func constructCreateImageRequestOpts(opts entity.CreateUpdateOptions, extraOption *ExtraOption) RequestOption {
-	return RequestOption{
-		Action: CREATE,
-		Resource: consts.Image,
-		ResourceLocation: extraOption.ResourceLocation,
-		RequestSuffix: "",
-		Body: opts,
-		Headers: map[string]string{consts.AuthToken: defaultController.Token()},
-	}
-}
-
-
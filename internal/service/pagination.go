@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/valyala/fasthttp"
+	"net/url"
+)
+
+// PageStyle is how a service tells the client where the next page starts.
+type PageStyle int
+
+const (
+	// LinksPaging follows the href of the "next" entry of <key>_links, as
+	// returned by neutron and octavia.
+	LinksPaging PageStyle = iota
+	// MarkerPaging follows <key>_links as nova and cinder return them once
+	// their max limit truncates a list, and otherwise asks for the page after
+	// the last item with marker while full pages come back.
+	MarkerPaging
+	// NextPaging follows the top level "next" url of glance.
+	NextPaging
+)
+
+type listOptions struct {
+	pageSize     int
+}
+
+// ListOption tunes how a List method pages through its collection.
+type ListOption func(*listOptions)
+
+// WithPageSize requests pages of size items, the service default otherwise.
+func WithPageSize(size int) ListOption {
+	return func(o *listOptions) {
+		o.pageSize = size
+	}
+}
+
+// pager walks the pages of a collection and returns each page body with the
+// raw items found under key.
+type pager struct {
+	s            *Controller
+	extra        ExtraOption
+	key          string
+	style        PageStyle
+	pageSize     int
+	query        string
+	done         bool
+}
+
+func (s *Controller) newPager(extra *ExtraOption, key string, style PageStyle, opts []ListOption) *pager {
+	var o listOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	query := extra.ResourceSuffix
+	if o.pageSize > 0 {
+		query = joinQuery(query, fmt.Sprintf("limit=%d", o.pageSize))
+	}
+	return &pager{s: s, extra: *extra, key: key, style: style, pageSize: o.pageSize, query: query}
+}
+
+func (p *pager) next(ctx context.Context) ([]byte, []json.RawMessage, error) {
+	if p.done {
+		return nil, nil, nil
+	}
+	extra := p.extra
+	extra.ResourceSuffix = p.query
+	resp, err := p.s.wrapper(constructListRequestOpts)(ctx, nil, &extra)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer fasthttp.ReleaseResponse(resp)
+	body := append([]byte(nil), resp.Body()...)
+
+	var page map[string]json.RawMessage
+	if err = json.Unmarshal(body, &page); err != nil {
+		return nil, nil, err
+	}
+	var items []json.RawMessage
+	if raw, ok := page[p.key]; ok {
+		if err = json.Unmarshal(raw, &items); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	query, ok, err := p.nextQuery(page, items)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok || query == p.query {
+		p.done = true
+	}
+	p.query = query
+	return body, items, nil
+}
+
+// nextQuery returns the query string of the page following page.
+func (p *pager) nextQuery(page map[string]json.RawMessage, items []json.RawMessage) (string, bool, error) {
+	if len(items) == 0 {
+		return "", false, nil
+	}
+	if p.style == NextPaging {
+		var next string
+		if raw, ok := page["next"]; ok {
+			if err := json.Unmarshal(raw, &next); err != nil {
+				return "", false, err
+			}
+		}
+		return queryOf(next)
+	}
+
+	if raw, ok := page[p.key+"_links"]; ok {
+		var links []struct {
+			Href    string  `json:"href"`
+			Rel     string  `json:"rel"`
+		}
+		if err := json.Unmarshal(raw, &links); err != nil {
+			return "", false, err
+		}
+		for _, link := range links {
+			if link.Rel == "next" {
+				return queryOf(link.Href)
+			}
+		}
+	}
+	if p.style != MarkerPaging || p.pageSize <= 0 || len(items) < p.pageSize {
+		return "", false, nil
+	}
+	var last struct {
+		Id      string  `json:"id"`
+	}
+	if err := json.Unmarshal(items[len(items)-1], &last); err != nil {
+		return "", false, err
+	}
+	if last.Id == "" {
+		return "", false, nil
+	}
+	query := joinQuery(p.extra.ResourceSuffix, fmt.Sprintf("limit=%d", p.pageSize))
+	return joinQuery(query, "marker="+url.QueryEscape(last.Id)), true, nil
+}
+
+// listAll drains p and decodes every item into out as if the service had
+// returned the whole collection in one page.
+func (s *Controller) listAll(ctx context.Context, p *pager, out interface{}) error {
+	all := make([]json.RawMessage, 0)
+	for !p.done {
+		_, items, err := p.next(ctx)
+		if err != nil {
+			return err
+		}
+		all = append(all, items...)
+	}
+	body, err := json.Marshal(map[string]interface{}{p.key: all, "count": len(all)})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, out)
+}
+
+// Iterator streams a collection page by page, each page decoded into T:
+//
+//	it := controller.IterPorts(ctx, WithPageSize(200))
+//	for it.Next(ctx) {
+//		ports := it.Page()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type Iterator[T any] struct {
+	pager        *pager
+	page         T
+	err          error
+}
+
+func newIterator[T any](p *pager, err error) *Iterator[T] {
+	return &Iterator[T]{pager: p, err: err}
+}
+
+// Next fetches the next page, it returns false once the collection is
+// exhausted or a request failed.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil || it.pager.done {
+		return false
+	}
+	body, items, err := it.pager.next(ctx)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	if len(items) == 0 {
+		return false
+	}
+	var page T
+	if err = json.Unmarshal(body, &page); err != nil {
+		it.err = err
+		return false
+	}
+	it.page = page
+	return true
+}
+
+func (it *Iterator[T]) Page() T {
+	return it.page
+}
+
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+func joinQuery(query, param string) string {
+	if query == "" {
+		return param
+	}
+	return query + "&" + param
+}
+
+// queryOf returns the query string of a next link, links carry the same
+// path as the first request so only the query moves.
+func queryOf(link string) (string, bool, error) {
+	if link == "" {
+		return "", false, nil
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid next page link %q: %w", link, err)
+	}
+	if u.RawQuery == "" {
+		return "", false, nil
+	}
+	return u.RawQuery, true, nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestQueryOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		link   string
+		want   string
+		ok     bool
+		err    bool
+	}{
+		{"empty", "", "", false, false},
+		{"absolute", "http://ctl:9696/v2.0/ports?limit=2&marker=p2", "limit=2&marker=p2", true, false},
+		{"relative", "/v2/images?marker=i2", "marker=i2", true, false},
+		{"no query", "http://ctl:9696/v2.0/ports", "", false, false},
+		{"invalid", "http://ctl:9696/%zz?marker=p2", "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := queryOf(tt.link)
+			if got != tt.want || ok != tt.ok || (err != nil) != tt.err {
+				t.Errorf("queryOf(%q) = %q, %v, %v, want %q, %v, error %v", tt.link, got, ok, err, tt.want, tt.ok, tt.err)
+			}
+		})
+	}
+}
+
+func TestNextQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		style     PageStyle
+		pageSize  int
+		suffix    string
+		page      string
+		want      string
+		ok        bool
+	}{
+		{
+			name: "links",
+			style: LinksPaging,
+			page: `{"ports": [{"id": "p1"}], "ports_links": [{"rel": "previous", "href": "http://ctl/v2.0/ports?marker=p0&page_reverse=True"}, {"rel": "next", "href": "http://ctl/v2.0/ports?limit=1&marker=p1"}]}`,
+			want: "limit=1&marker=p1",
+			ok: true,
+		},
+		{
+			name: "last links page",
+			style: LinksPaging,
+			page: `{"ports": [{"id": "p1"}], "ports_links": [{"rel": "previous", "href": "http://ctl/v2.0/ports?marker=p0&page_reverse=True"}]}`,
+		},
+		{
+			name: "links page without links",
+			style: LinksPaging,
+			pageSize: 1,
+			page: `{"ports": [{"id": "p1"}]}`,
+		},
+		{
+			name: "empty page",
+			style: LinksPaging,
+			page: `{"ports": [], "ports_links": [{"rel": "next", "href": "http://ctl/v2.0/ports?marker=p1"}]}`,
+		},
+		{
+			name: "marker links",
+			style: MarkerPaging,
+			page: `{"ports": [{"id": "p1"}], "ports_links": [{"rel": "next", "href": "http://ctl/v2.1/servers?marker=p1"}]}`,
+			want: "marker=p1",
+			ok: true,
+		},
+		{
+			name: "full marker page",
+			style: MarkerPaging,
+			pageSize: 2,
+			suffix: "all_tenants=1",
+			page: `{"ports": [{"id": "p1"}, {"id": "p2"}]}`,
+			want: "all_tenants=1&limit=2&marker=p2",
+			ok: true,
+		},
+		{
+			name: "short marker page",
+			style: MarkerPaging,
+			pageSize: 2,
+			page: `{"ports": [{"id": "p1"}]}`,
+		},
+		{
+			name: "marker page without page size",
+			style: MarkerPaging,
+			page: `{"ports": [{"id": "p1"}, {"id": "p2"}]}`,
+		},
+		{
+			name: "next",
+			style: NextPaging,
+			page: `{"ports": [{"id": "p1"}], "next": "/v2/images?marker=p1"}`,
+			want: "marker=p1",
+			ok: true,
+		},
+		{
+			name: "last next page",
+			style: NextPaging,
+			page: `{"ports": [{"id": "p1"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var page map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(tt.page), &page); err != nil {
+				t.Fatal(err)
+			}
+			var items []json.RawMessage
+			if err := json.Unmarshal(page["ports"], &items); err != nil {
+				t.Fatal(err)
+			}
+			p := &pager{extra: ExtraOption{ResourceSuffix: tt.suffix}, key: "ports", style: tt.style, pageSize: tt.pageSize}
+			got, ok, err := p.nextQuery(page, items)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("nextQuery() = %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestJoinQuery(t *testing.T) {
+	if got := joinQuery("", "limit=2"); got != "limit=2" {
+		t.Errorf("joinQuery() = %q", got)
+	}
+	if got := joinQuery("project_id=p1", "limit=2"); got != "project_id=p1&limit=2" {
+		t.Errorf("joinQuery() = %q", got)
+	}
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/valyala/fasthttp"
+	"go-openstackclient/consts"
+	"go-openstackclient/internal/entity"
+	"log"
+	"sort"
+)
+
+// Neutron keeps quotas below quotas/<project>, nova and cinder below
+// os-quota-sets/<project>, cinder prefixed with the project of the caller.
+
+func (s *Controller) quotaLocation(ctx context.Context, resource, projectId string) (string, error) {
+	switch resource {
+	case consts.QUOTA:
+		return fmt.Sprintf("%s/%s", consts.QUOTAS, projectId), nil
+	case consts.VOLUMEQUOTA:
+		return s.projectPath(ctx, "%s/%s", consts.QUOTASETS, projectId)
+	default:
+		return fmt.Sprintf("%s/%s", consts.QUOTASETS, projectId), nil
+	}
+}
+
+// quotaRequest sends action to the quota of projectId in the service owning
+// resource and decodes the response into out when it is set.
+func (s *Controller) quotaRequest(ctx context.Context, action, resource, projectId, suffix, query string, body entity.CreateUpdateOptions, out interface{}) error {
+	location, err := s.quotaLocation(ctx, resource, projectId)
+	if err != nil {
+		return err
+	}
+	if suffix != "" {
+		location = fmt.Sprintf("%s/%s", location, suffix)
+	}
+	resp, err := s.Do(ctx, RequestOption{
+		Action: action,
+		Resource: resource,
+		ResourceLocation: location,
+		RequestSuffix: query,
+		Body: body,
+		Headers: make(map[string]string),
+	})
+	if err != nil {
+		return err
+	}
+	defer fasthttp.ReleaseResponse(resp)
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Body(), out)
+}
+
+func (s *Controller) GetNetworkQuota(ctx context.Context, projectId string) (entity.NetworkQuota, error) {
+	var quota entity.NetworkQuotaMap
+	err := s.quotaRequest(ctx, GET, consts.QUOTA, projectId, "", "", nil, &quota)
+	return quota.NetworkQuota, err
+}
+
+// SetNetworkQuota updates the limits set in opts, the nil ones are kept.
+func (s *Controller) SetNetworkQuota(ctx context.Context, projectId string, opts *entity.UpdateNetworkQuotaOpts) (entity.NetworkQuota, error) {
+	var updated entity.NetworkQuotaMap
+	err := s.quotaRequest(ctx, UPDATE, consts.QUOTA, projectId, "", "", opts, &updated)
+	if err == nil {
+		log.Println("==============Set network quota success", projectId)
+	}
+	return updated.NetworkQuota, err
+}
+
+// ResetNetworkQuota drops the limits of the project back to the defaults.
+func (s *Controller) ResetNetworkQuota(ctx context.Context, projectId string) error {
+	err := s.quotaRequest(ctx, DELETE, consts.QUOTA, projectId, "", "", nil, nil)
+	if err == nil {
+		log.Println("==============Reset network quota success", projectId)
+	}
+	return err
+}
+
+func (s *Controller) GetComputeQuota(ctx context.Context, projectId string) (entity.ComputeQuota, error) {
+	var quota entity.ComputeQuotaMap
+	err := s.quotaRequest(ctx, GET, consts.COMPUTEQUOTA, projectId, "", "", nil, &quota)
+	return quota.ComputeQuota, err
+}
+
+func (s *Controller) SetComputeQuota(ctx context.Context, projectId string, opts *entity.UpdateComputeQuotaOpts) (entity.ComputeQuota, error) {
+	var updated entity.ComputeQuotaMap
+	err := s.quotaRequest(ctx, UPDATE, consts.COMPUTEQUOTA, projectId, "", "", opts, &updated)
+	if err == nil {
+		log.Println("==============Set compute quota success", projectId)
+	}
+	return updated.ComputeQuota, err
+}
+
+func (s *Controller) ResetComputeQuota(ctx context.Context, projectId string) error {
+	err := s.quotaRequest(ctx, DELETE, consts.COMPUTEQUOTA, projectId, "", "", nil, nil)
+	if err == nil {
+		log.Println("==============Reset compute quota success", projectId)
+	}
+	return err
+}
+
+func (s *Controller) GetVolumeQuota(ctx context.Context, projectId string) (entity.VolumeQuota, error) {
+	var quota entity.VolumeQuotaMap
+	err := s.quotaRequest(ctx, GET, consts.VOLUMEQUOTA, projectId, "", "", nil, &quota)
+	return quota.VolumeQuota, err
+}
+
+func (s *Controller) SetVolumeQuota(ctx context.Context, projectId string, opts *entity.UpdateVolumeQuotaOpts) (entity.VolumeQuota, error) {
+	var updated entity.VolumeQuotaMap
+	err := s.quotaRequest(ctx, UPDATE, consts.VOLUMEQUOTA, projectId, "", "", opts, &updated)
+	if err == nil {
+		log.Println("==============Set volume quota success", projectId)
+	}
+	return updated.VolumeQuota, err
+}
+
+func (s *Controller) ResetVolumeQuota(ctx context.Context, projectId string) error {
+	err := s.quotaRequest(ctx, DELETE, consts.VOLUMEQUOTA, projectId, "", "", nil, nil)
+	if err == nil {
+		log.Println("==============Reset volume quota success", projectId)
+	}
+	return err
+}
+
+// QuotaWarning is a resource whose usage came within the threshold of its
+// limit, Consumed counts the reserved amount along with the used one.
+type QuotaWarning struct {
+	Service           string
+	Resource          string
+	Limit             int
+	Consumed          int
+	Percent           float64
+}
+
+// QuotaUsage is the limits and usage of a project by service and resource.
+// NearLimit lists the resources used within the threshold of their limit.
+type QuotaUsage struct {
+	Project           string
+	ProjectId         string
+	Usage             map[string]map[string]entity.QuotaDetail
+	NearLimit         []QuotaWarning
+}
+
+func (u QuotaUsage) Flagged() bool {
+	return len(u.NearLimit) != 0
+}
+
+// GetQuotaUsage reads the neutron, nova and cinder limits of projectName
+// along with their usage and flags the resources used above
+// (100 - withinPercent)% of their limit. Unlimited resources are never
+// flagged.
+func (s *Controller) GetQuotaUsage(ctx context.Context, projectName string, withinPercent float64) (QuotaUsage, error) {
+	usage := QuotaUsage{Project: projectName, Usage: make(map[string]map[string]entity.QuotaDetail)}
+	projectId, err := s.projectIdByName(ctx, projectName)
+	if err != nil {
+		return usage, err
+	}
+	usage.ProjectId = projectId
+
+	requests := []struct {
+		service, resource, suffix, query, key string
+	}{
+		{consts.NEUTRON, consts.QUOTA, "details", "", consts.QUOTA},
+		{consts.NOVA, consts.COMPUTEQUOTA, "detail", "", consts.QUOTASET},
+		{consts.CINDER, consts.VOLUMEQUOTA, "", "usage=true", consts.QUOTASET},
+	}
+	for _, r := range requests {
+		var body map[string]map[string]json.RawMessage
+		if err = s.quotaRequest(ctx, GET, r.resource, projectId, r.suffix, r.query, nil, &body); err != nil {
+			return usage, fmt.Errorf("get %s quota usage of %s: %w", r.service, projectName, err)
+		}
+		details := make(map[string]entity.QuotaDetail)
+		for resource, raw := range body[r.key] {
+			var detail entity.QuotaDetail
+			// id and the per volume type entries of cinder are no details
+			if json.Unmarshal(raw, &detail) != nil {
+				continue
+			}
+			details[resource] = detail
+		}
+		usage.Usage[r.service] = details
+	}
+	usage.NearLimit = nearLimit(usage.Usage, withinPercent)
+	log.Printf("==============Get quota usage of %s success, %d near limit", projectName, len(usage.NearLimit))
+	return usage, nil
+}
+
+// QuotaUsageReport gets the usage of every project, use Flagged to pick the
+// ones to resize.
+func (s *Controller) QuotaUsageReport(ctx context.Context, projects []string, withinPercent float64) ([]QuotaUsage, error) {
+	report := make([]QuotaUsage, 0, len(projects))
+	for _, projectName := range projects {
+		usage, err := s.GetQuotaUsage(ctx, projectName, withinPercent)
+		if err != nil {
+			return report, err
+		}
+		report = append(report, usage)
+	}
+	return report, nil
+}
+
+func nearLimit(usage map[string]map[string]entity.QuotaDetail, withinPercent float64) []QuotaWarning {
+	warnings := make([]QuotaWarning, 0)
+	for service, details := range usage {
+		for resource, detail := range details {
+			if detail.Limit <= 0 {
+				continue
+			}
+			percent := float64(detail.Consumed()) * 100 / float64(detail.Limit)
+			if percent >= 100-withinPercent {
+				warnings = append(warnings, QuotaWarning{
+					Service: service, Resource: resource,
+					Limit: detail.Limit, Consumed: detail.Consumed(), Percent: percent,
+				})
+			}
+		}
+	}
+	sort.Slice(warnings, func(i, j int) bool {
+		return warnings[i].Percent > warnings[j].Percent
+	})
+	return warnings
+}
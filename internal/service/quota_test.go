@@ -0,0 +1,30 @@
+package service
+
+import (
+	"go-openstackclient/internal/entity"
+	"reflect"
+	"testing"
+)
+
+func TestNearLimit(t *testing.T) {
+	usage := map[string]map[string]entity.QuotaDetail{
+		"network": {
+			"port": {Limit: 100, Used: 85, Reserved: 10},
+			"network": {Limit: 100, Used: 10},
+			"router": {Limit: -1, Used: 500},
+		},
+		"compute": {
+			"cores": {Limit: 20, InUse: 20},
+			"instances": {Limit: 10, InUse: 8, Reserved: 1},
+			"key_pairs": {Limit: 0},
+		},
+	}
+	want := []QuotaWarning{
+		{Service: "compute", Resource: "cores", Limit: 20, Consumed: 20, Percent: 100},
+		{Service: "network", Resource: "port", Limit: 100, Consumed: 95, Percent: 95},
+		{Service: "compute", Resource: "instances", Limit: 10, Consumed: 9, Percent: 90},
+	}
+	if got := nearLimit(usage, 10); !reflect.DeepEqual(got, want) {
+		t.Errorf("nearLimit() = %+v, want %+v", got, want)
+	}
+}
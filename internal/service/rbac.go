@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-openstackclient/consts"
+	"go-openstackclient/internal/entity"
+	"log"
+	"net/url"
+)
+
+type RbacPolicyClient = ResourceClient[entity.RbacPolicy, *entity.CreateRbacPolicyOpts, *entity.UpdateRbacPolicyOpts]
+
+func (s *Controller) RbacPolicies() *RbacPolicyClient {
+	return NewResourceClient[entity.RbacPolicy, *entity.CreateRbacPolicyOpts, *entity.UpdateRbacPolicyOpts](s, ResourceSpec{
+		Resource: consts.RBACPOLICY, Collection: consts.RBACPOLICIES,
+		Key: consts.RBACPOLICY, PluralKey: consts.RBACPOLICIES, Paging: LinksPaging})
+}
+
+// targetProjectId resolves a project name through keystone, "*" stands for
+// every project and is kept as it is.
+func (s *Controller) targetProjectId(ctx context.Context, projectName string) (string, error) {
+	if projectName == "*" {
+		return projectName, nil
+	}
+	return s.projectIdByName(ctx, projectName)
+}
+
+// projectIdByName is GetProjectId failing with ErrNotFound for an unknown
+// project.
+func (s *Controller) projectIdByName(ctx context.Context, projectName string) (string, error) {
+	projectId, err := s.GetProjectId(ctx, projectName)
+	if err != nil {
+		return "", err
+	}
+	if projectId == "" {
+		return "", fmt.Errorf("project %s: %w", projectName, ErrNotFound)
+	}
+	return projectId, nil
+}
+
+func (s *Controller) listSharePolicies(ctx context.Context, objectType, objectId, targetTenant string) ([]entity.RbacPolicy, error) {
+	query := fmt.Sprintf("object_type=%s&object_id=%s&action=%s&target_tenant=%s",
+		objectType, objectId, consts.ACCESSASSHARED, url.QueryEscape(targetTenant))
+	return s.RbacPolicies().List(ctx, query)
+}
+
+// ShareResource shares the object, a network, qos_policy or security_group
+// among others, with targetProject, a project name or "*". Sharing twice
+// returns the existing policy.
+func (s *Controller) ShareResource(ctx context.Context, objectType, objectId, targetProject string) (entity.RbacPolicy, error) {
+	targetTenant, err := s.targetProjectId(ctx, targetProject)
+	if err != nil {
+		return entity.RbacPolicy{}, err
+	}
+	policies, err := s.listSharePolicies(ctx, objectType, objectId, targetTenant)
+	if err != nil {
+		return entity.RbacPolicy{}, err
+	}
+	if len(policies) > 0 {
+		return policies[0], nil
+	}
+	policy, err := s.RbacPolicies().Create(ctx, &entity.CreateRbacPolicyOpts{
+		ObjectType: objectType,
+		ObjectID: objectId,
+		Action: consts.ACCESSASSHARED,
+		TargetTenant: targetTenant,
+	})
+	if err == nil {
+		log.Printf("==============Share %s %s with %s success", objectType, objectId, targetProject)
+	}
+	return policy, err
+}
+
+// UnshareResource removes the policies sharing the object with
+// targetProject, neutron refuses while the project still uses the object.
+func (s *Controller) UnshareResource(ctx context.Context, objectType, objectId, targetProject string) error {
+	targetTenant, err := s.targetProjectId(ctx, targetProject)
+	if err != nil {
+		return err
+	}
+	policies, err := s.listSharePolicies(ctx, objectType, objectId, targetTenant)
+	if err != nil {
+		return err
+	}
+	if len(policies) == 0 {
+		return fmt.Errorf("%s %s shared with %s: %w", objectType, objectId, targetProject, ErrNotFound)
+	}
+	for _, policy := range policies {
+		if err = s.RbacPolicies().Delete(ctx, policy.Id); err != nil {
+			return err
+		}
+	}
+	log.Printf("==============Unshare %s %s with %s success", objectType, objectId, targetProject)
+	return nil
+}
+
+// SharingReport lists the RBAC policies granting a project access to the
+// objects of others, SharedTo, including the ones granted to every project,
+// and the ones sharing its own objects, SharedFrom.
+type SharingReport struct {
+	Project           string
+	ProjectId         string
+	SharedTo          []entity.RbacPolicy
+	SharedFrom        []entity.RbacPolicy
+}
+
+func (s *Controller) ProjectSharingReport(ctx context.Context, projectName string) (SharingReport, error) {
+	report := SharingReport{Project: projectName}
+	projectId, err := s.targetProjectId(ctx, projectName)
+	if err != nil {
+		return report, err
+	}
+	report.ProjectId = projectId
+
+	for _, target := range []string{projectId, "*"} {
+		policies, err := s.RbacPolicies().List(ctx, "target_tenant="+url.QueryEscape(target))
+		if err != nil {
+			return report, err
+		}
+		for _, policy := range policies {
+			if policy.ProjectId != projectId {
+				report.SharedTo = append(report.SharedTo, policy)
+			}
+		}
+	}
+
+	policies, err := s.RbacPolicies().List(ctx, "project_id="+projectId)
+	if err != nil {
+		return report, err
+	}
+	for _, policy := range policies {
+		if policy.TargetTenant != projectId {
+			report.SharedFrom = append(report.SharedFrom, policy)
+		}
+	}
+	return report, nil
+}
+
+// DeleteRbacPolicies is called by the Cleaner before the networks, QoS
+// policies and security groups, a network shared with another project can
+// not be deleted while the policy remains.
+func (s *Controller) DeleteRbacPolicies(ctx context.Context) error {
+	return s.RbacPolicies().DeleteAll(ctx)
+}
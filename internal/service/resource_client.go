@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/valyala/fasthttp"
+	"go-openstackclient/internal/entity"
+	"log"
+	"net/url"
+)
+
+// ErrNotFound is wrapped by FindByName when no resource has the name.
+var ErrNotFound = errors.New("resource not found")
+
+// ResourceSpec locates a collection. Resource is the resource type the
+// service registry routes to the owning service, Collection the path below
+// the service endpoint, Key the JSON envelope of one item and PluralKey the
+// one of a page, Paging how the service pages through the collection.
+// ProjectScoped collections live below the project id, as cinder's do.
+type ResourceSpec struct {
+	Resource         string
+	Collection       string
+	Key              string
+	PluralKey        string
+	Paging           PageStyle
+	ProjectScoped    bool
+}
+
+// ResourceClient is the typed Create/Get/List/Update/Delete/FindByName of
+// one collection, T is the item and CreateOpts and UpdateOpts the request
+// bodies. Resources without an update use entity.CreateUpdateOptions.
+type ResourceClient[T any, CreateOpts entity.CreateUpdateOptions, UpdateOpts entity.CreateUpdateOptions] struct {
+	s                *Controller
+	spec             ResourceSpec
+}
+
+func NewResourceClient[T any, CreateOpts entity.CreateUpdateOptions, UpdateOpts entity.CreateUpdateOptions](s *Controller, spec ResourceSpec) *ResourceClient[T, CreateOpts, UpdateOpts] {
+	return &ResourceClient[T, CreateOpts, UpdateOpts]{s: s, spec: spec}
+}
+
+func (r *ResourceClient[T, CreateOpts, UpdateOpts]) Spec() ResourceSpec {
+	return r.spec
+}
+
+func (r *ResourceClient[T, CreateOpts, UpdateOpts]) location(ctx context.Context, id string) (string, error) {
+	collection := r.spec.Collection
+	if r.spec.ProjectScoped {
+		var err error
+		if collection, err = r.s.projectPath(ctx, "%s", collection); err != nil {
+			return "", err
+		}
+	}
+	if id == "" {
+		return collection, nil
+	}
+	return fmt.Sprintf("%s/%s", collection, id), nil
+}
+
+// do sends a request to the collection, or to the item id when it is set,
+// and decodes the item envelope of the response into T.
+func (r *ResourceClient[T, CreateOpts, UpdateOpts]) do(ctx context.Context, action, id string, body entity.CreateUpdateOptions) (T, error) {
+	var item T
+	location, err := r.location(ctx, id)
+	if err != nil {
+		return item, err
+	}
+	resp, err := r.s.Do(ctx, RequestOption{
+		Action: action,
+		Resource: r.spec.Resource,
+		ResourceLocation: location,
+		Body: body,
+		Headers: make(map[string]string),
+	})
+	if err != nil {
+		return item, err
+	}
+	defer fasthttp.ReleaseResponse(resp)
+
+	err = decodeEnvelope(resp.Body(), r.spec.Key, &item)
+	return item, err
+}
+
+func (r *ResourceClient[T, CreateOpts, UpdateOpts]) Create(ctx context.Context, opts CreateOpts) (T, error) {
+	item, err := r.do(ctx, CREATE, "", opts)
+	if err == nil {
+		log.Printf("==============Create %s success", r.spec.Key)
+	}
+	return item, err
+}
+
+func (r *ResourceClient[T, CreateOpts, UpdateOpts]) Get(ctx context.Context, id string) (T, error) {
+	return r.do(ctx, GET, id, nil)
+}
+
+func (r *ResourceClient[T, CreateOpts, UpdateOpts]) Update(ctx context.Context, id string, opts UpdateOpts) (T, error) {
+	item, err := r.do(ctx, UPDATE, id, opts)
+	if err == nil {
+		log.Printf("==============Update %s success %s", r.spec.Key, id)
+	}
+	return item, err
+}
+
+func (r *ResourceClient[T, CreateOpts, UpdateOpts]) Delete(ctx context.Context, id string) error {
+	location, err := r.location(ctx, id)
+	if err != nil {
+		return err
+	}
+	resp, err := r.s.Do(ctx, RequestOption{
+		Action: DELETE,
+		Resource: r.spec.Resource,
+		ResourceLocation: location,
+		Headers: make(map[string]string),
+	})
+	if err != nil {
+		return err
+	}
+	fasthttp.ReleaseResponse(resp)
+	log.Printf("==============Delete %s success %s", r.spec.Key, id)
+	return nil
+}
+
+// List returns every item matching query, a url query string such as
+// "project_id=...", following the pages of the collection.
+func (r *ResourceClient[T, CreateOpts, UpdateOpts]) List(ctx context.Context, query string, opts ...ListOption) ([]T, error) {
+	items := make([]T, 0)
+	p, err := r.pager(ctx, query, opts)
+	if err != nil {
+		return items, err
+	}
+	for !p.done {
+		_, page, err := p.next(ctx)
+		if err != nil {
+			return items, err
+		}
+		for _, raw := range page {
+			var item T
+			if err = json.Unmarshal(raw, &item); err != nil {
+				return items, err
+			}
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// pager pages through the items matching query, for the Iter methods
+// streaming whole pages.
+func (r *ResourceClient[T, CreateOpts, UpdateOpts]) pager(ctx context.Context, query string, opts []ListOption) (*pager, error) {
+	location, err := r.location(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return r.s.newPager(&ExtraOption{
+		Resource: r.spec.Resource, ResourceLocation: location,
+		ResourceSuffix: query}, r.spec.PluralKey, r.spec.Paging, opts), nil
+}
+
+// projectPager pages through the items of the controller's project, every
+// project for the admin.
+func (r *ResourceClient[T, CreateOpts, UpdateOpts]) projectPager(ctx context.Context, opts []ListOption) (*pager, error) {
+	query, err := r.s.projectFilter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.pager(ctx, query, opts)
+}
+
+// FindByName returns the only item named name, wrapping ErrNotFound when
+// there is none and failing when the name is ambiguous.
+func (r *ResourceClient[T, CreateOpts, UpdateOpts]) FindByName(ctx context.Context, name string) (T, error) {
+	var item T
+	items, err := r.List(ctx, "name="+url.QueryEscape(name))
+	if err != nil {
+		return item, err
+	}
+	switch len(items) {
+	case 0:
+		return item, fmt.Errorf("%s %q: %w", r.spec.Key, name, ErrNotFound)
+	case 1:
+		return items[0], nil
+	default:
+		return item, fmt.Errorf("found %d %s named %q, use the id instead", len(items), r.spec.PluralKey, name)
+	}
+}
+
+// DeleteAll deletes the items of the controller's project concurrently and
+// reports each result into the delete channel of the spec's resource type,
+// as the Delete<Resources> methods called by the Cleaner do.
+func (r *ResourceClient[T, CreateOpts, UpdateOpts]) DeleteAll(ctx context.Context) error {
+	ids, err := r.ProjectIDs(ctx)
+	if err != nil {
+		return err
+	}
+	if err = r.s.deleteConcurrently(ctx, r.spec.Resource, r.spec.Key+"_id", ids, r.Delete); err != nil {
+		return err
+	}
+	log.Printf("%s were deleted completely", r.spec.PluralKey)
+	return nil
+}
+
+// ProjectIDs lists the ids of the items of the controller's project.
+func (r *ResourceClient[T, CreateOpts, UpdateOpts]) ProjectIDs(ctx context.Context) ([]string, error) {
+	query, err := r.s.projectFilter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, err := r.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		id, err := resourceID(item)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// call sends action to the sub-resource path of the item id, e.g. the
+// insert_rule of a firewall policy, and decodes the response into out when
+// it is set.
+func (r *ResourceClient[T, CreateOpts, UpdateOpts]) call(ctx context.Context, action, id, path string, body entity.CreateUpdateOptions, out interface{}) error {
+	location, err := r.location(ctx, id)
+	if err != nil {
+		return err
+	}
+	resp, err := r.s.Do(ctx, RequestOption{
+		Action: action,
+		Resource: r.spec.Resource,
+		ResourceLocation: fmt.Sprintf("%s/%s", location, path),
+		Body: body,
+		Headers: make(map[string]string),
+	})
+	if err != nil {
+		return err
+	}
+	defer fasthttp.ReleaseResponse(resp)
+	if out == nil || len(resp.Body()) == 0 {
+		return nil
+	}
+	return json.Unmarshal(resp.Body(), out)
+}
+
+// deleteConcurrently deletes ids with del in parallel and reports each
+// result, keyed by idKey, into the delete channel of resourceType.
+func (s *Controller) deleteConcurrently(ctx context.Context, resourceType, idKey string, ids []string, del func(ctx context.Context, id string) error) error {
+	ch := s.MakeDeleteChannel(resourceType, len(ids))
+
+	for _, id := range ids {
+		tempId := id
+		go func() {
+			outputObj := Output{ParametersMap: map[string]string{idKey: tempId}}
+			ch <- resultOutput(outputObj, del(ctx, tempId))
+		}()
+	}
+	return waitDeleteChannel(ctx, ch)
+}
+
+// resourceID reads the id field every OpenStack resource carries.
+func resourceID(item interface{}) (string, error) {
+	b, err := json.Marshal(item)
+	if err != nil {
+		return "", err
+	}
+	var resource struct {
+		Id      string  `json:"id"`
+	}
+	if err = json.Unmarshal(b, &resource); err != nil {
+		return "", err
+	}
+	if resource.Id == "" {
+		return "", fmt.Errorf("%T has no id", item)
+	}
+	return resource.Id, nil
+}
+
+// decodeEnvelope decodes the value under key of body into out, the whole
+// body when key is empty.
+func decodeEnvelope(body []byte, key string, out interface{}) error {
+	if key == "" {
+		return json.Unmarshal(body, out)
+	}
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return err
+	}
+	raw, ok := envelope[key]
+	if !ok {
+		return fmt.Errorf("response has no %q object", key)
+	}
+	return json.Unmarshal(raw, out)
+}
@@ -1,5 +1,11 @@
 package service
 
+import (
+	"fmt"
+	"go-openstackclient/consts"
+	"go-openstackclient/internal/entity"
+)
+
 var (
 	ResourcesMap      = make(map[string]Resource)
 )
@@ -11,3 +17,175 @@ type Resource struct {
     Dependencies       map[string]string
     Done               bool
 }
+
+// typed clients of the collections managed through ResourceClient, a new
+// collection only needs its entity types and a spec here
+
+type (
+	NetworkClient           = ResourceClient[entity.Network, *entity.CreateNetworkOpts, entity.CreateUpdateOptions]
+	SubnetClient            = ResourceClient[entity.Subnet, *entity.CreateSubnetOpts, entity.CreateUpdateOptions]
+	PortClient              = ResourceClient[entity.Port, *entity.CreatePortOpts, entity.CreateUpdateOptions]
+	RouterClient            = ResourceClient[entity.Router, *entity.CreateRouterOpts, *entity.UpdateRouterOpts]
+	FloatingIPClient        = ResourceClient[entity.Floatingip, *entity.CreateFipOpts, *entity.UpdateFipOpts]
+	SecurityGroupClient     = ResourceClient[entity.SecurityGroup, *entity.CreateSecurityGroupOpts, entity.CreateUpdateOptions]
+	SecurityGroupRuleClient = ResourceClient[entity.SecurityGroupRule, *entity.CreateSecurityRuleOpts, entity.CreateUpdateOptions]
+	QosPolicyClient         = ResourceClient[entity.Policy, *entity.CreateQosPolicyOpts, entity.CreateUpdateOptions]
+	LoadbalancerClient      = ResourceClient[entity.Loadbalancer, *entity.CreateLoadbalancerOpts, *entity.UpdateLoadbalancerOpts]
+	ListenerClient          = ResourceClient[entity.Listener, *entity.CreateListenerOpts, *entity.UpdateListenerOpts]
+	PoolClient              = ResourceClient[entity.Pool, *entity.CreatePoolOpts, *entity.UpdatePoolOpts]
+	HealthMonitorClient     = ResourceClient[entity.Healthmonitor, *entity.CreateHealthMonitorOpts, *entity.UpdateHealthMonitorOpts]
+	L7PolicyClient          = ResourceClient[entity.L7Policy, *entity.CreateL7PoliciesOpts, entity.CreateUpdateOptions]
+	VpcConnectionClient     = ResourceClient[entity.VpcConnection, *entity.CreateVpcConnectionOpts, *entity.UpdateVpcConnectionOpts]
+	PortForwardingClient    = ResourceClient[entity.PortForwarding, *entity.CreatePortForwardingOpts, entity.CreateUpdateOptions]
+	MemberClient            = ResourceClient[entity.Member, *entity.CreateMemberOpts, entity.CreateUpdateOptions]
+	ServerClient            = ResourceClient[entity.Server, *entity.CreateInstanceOpts, entity.CreateUpdateOptions]
+	ImageClient             = ResourceClient[entity.ImageMap, *entity.CreateImageOpts, entity.CreateUpdateOptions]
+	VolumeClient            = ResourceClient[entity.Volume, entity.CreateUpdateOptions, entity.CreateUpdateOptions]
+	SnapshotClient          = ResourceClient[entity.Snapshot, entity.CreateUpdateOptions, entity.CreateUpdateOptions]
+	VolumeTypeClient        = ResourceClient[entity.VolumeType, entity.CreateUpdateOptions, entity.CreateUpdateOptions]
+	QosSpecsClient          = ResourceClient[entity.QosSpecs, entity.CreateUpdateOptions, entity.CreateUpdateOptions]
+	CinderAttachmentClient  = ResourceClient[entity.Attachment, entity.CreateUpdateOptions, entity.CreateUpdateOptions]
+)
+
+func (s *Controller) Networks() *NetworkClient {
+	return NewResourceClient[entity.Network, *entity.CreateNetworkOpts, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.NETWORK, Collection: consts.NETWORKS,
+		Key: consts.NETWORK, PluralKey: consts.NETWORKS, Paging: LinksPaging})
+}
+
+func (s *Controller) Subnets() *SubnetClient {
+	return NewResourceClient[entity.Subnet, *entity.CreateSubnetOpts, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.SUBNET, Collection: consts.SUBNETS,
+		Key: consts.SUBNET, PluralKey: consts.SUBNETS, Paging: LinksPaging})
+}
+
+func (s *Controller) Ports() *PortClient {
+	return NewResourceClient[entity.Port, *entity.CreatePortOpts, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.PORT, Collection: consts.PORTS,
+		Key: consts.PORT, PluralKey: consts.PORTS, Paging: LinksPaging})
+}
+
+func (s *Controller) Routers() *RouterClient {
+	return NewResourceClient[entity.Router, *entity.CreateRouterOpts, *entity.UpdateRouterOpts](s, ResourceSpec{
+		Resource: consts.ROUTER, Collection: consts.ROUTERS,
+		Key: consts.ROUTER, PluralKey: consts.ROUTERS, Paging: LinksPaging})
+}
+
+func (s *Controller) FloatingIPs() *FloatingIPClient {
+	return NewResourceClient[entity.Floatingip, *entity.CreateFipOpts, *entity.UpdateFipOpts](s, ResourceSpec{
+		Resource: consts.FLOATINGIP, Collection: consts.FLOATINGIPS,
+		Key: consts.FLOATINGIP, PluralKey: consts.FLOATINGIPS, Paging: LinksPaging})
+}
+
+func (s *Controller) SecurityGroups() *SecurityGroupClient {
+	return NewResourceClient[entity.SecurityGroup, *entity.CreateSecurityGroupOpts, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.SECURITYGROUP, Collection: "security-groups",
+		Key: consts.SECURITYGROUP, PluralKey: consts.SECURITYGROUPS, Paging: LinksPaging})
+}
+
+func (s *Controller) SecurityGroupRules() *SecurityGroupRuleClient {
+	return NewResourceClient[entity.SecurityGroupRule, *entity.CreateSecurityRuleOpts, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.SECURITYGROUPRULE, Collection: "security-group-rules",
+		Key: consts.SECURITYGROUPRULE, PluralKey: consts.SECURITYGROUPRULES, Paging: LinksPaging})
+}
+
+func (s *Controller) QosPolicies() *QosPolicyClient {
+	return NewResourceClient[entity.Policy, *entity.CreateQosPolicyOpts, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.QOS_POLICY, Collection: "qos/policies",
+		Key: consts.POLICY, PluralKey: "policies", Paging: LinksPaging})
+}
+
+func (s *Controller) Loadbalancers() *LoadbalancerClient {
+	return NewResourceClient[entity.Loadbalancer, *entity.CreateLoadbalancerOpts, *entity.UpdateLoadbalancerOpts](s, ResourceSpec{
+		Resource: consts.LOADBALANCER, Collection: "lbaas/" + consts.LOADBALANCERS,
+		Key: consts.LOADBALANCER, PluralKey: consts.LOADBALANCERS, Paging: LinksPaging})
+}
+
+func (s *Controller) Listeners() *ListenerClient {
+	return NewResourceClient[entity.Listener, *entity.CreateListenerOpts, *entity.UpdateListenerOpts](s, ResourceSpec{
+		Resource: consts.LISTENER, Collection: "lbaas/" + consts.LISTENERS,
+		Key: consts.LISTENER, PluralKey: consts.LISTENERS, Paging: LinksPaging})
+}
+
+func (s *Controller) Pools() *PoolClient {
+	return NewResourceClient[entity.Pool, *entity.CreatePoolOpts, *entity.UpdatePoolOpts](s, ResourceSpec{
+		Resource: consts.POOL, Collection: "lbaas/" + consts.POOLS,
+		Key: consts.POOL, PluralKey: consts.POOLS, Paging: LinksPaging})
+}
+
+func (s *Controller) HealthMonitors() *HealthMonitorClient {
+	return NewResourceClient[entity.Healthmonitor, *entity.CreateHealthMonitorOpts, *entity.UpdateHealthMonitorOpts](s, ResourceSpec{
+		Resource: consts.HEALTHMONITOR, Collection: "lbaas/" + consts.HEALTHMONITORS,
+		Key: consts.HEALTHMONITOR, PluralKey: consts.HEALTHMONITORS, Paging: LinksPaging})
+}
+
+func (s *Controller) L7Policies() *L7PolicyClient {
+	return NewResourceClient[entity.L7Policy, *entity.CreateL7PoliciesOpts, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.L7POLICY, Collection: "lbaas/" + consts.L7POLICIES,
+		Key: consts.L7POLICY, PluralKey: consts.L7POLICIES, Paging: LinksPaging})
+}
+
+func (s *Controller) VpcConnections() *VpcConnectionClient {
+	return NewResourceClient[entity.VpcConnection, *entity.CreateVpcConnectionOpts, *entity.UpdateVpcConnectionOpts](s, ResourceSpec{
+		Resource: consts.VpcConnection, Collection: consts.VpcConnections,
+		Key: consts.VpcConnection, PluralKey: consts.VpcConnections, Paging: LinksPaging})
+}
+
+// PortForwardings are the port forwardings of the floating ip fipId.
+func (s *Controller) PortForwardings(fipId string) *PortForwardingClient {
+	return NewResourceClient[entity.PortForwarding, *entity.CreatePortForwardingOpts, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.PORTFORWARDING, Collection: fmt.Sprintf("%s/%s/%s", consts.FLOATINGIPS, fipId, consts.PORTFORWARDINGS),
+		Key: consts.PORTFORWARDING, PluralKey: consts.PORTFORWARDINGS, Paging: LinksPaging})
+}
+
+// PoolMembers are the members of the pool poolId.
+func (s *Controller) PoolMembers(poolId string) *MemberClient {
+	return NewResourceClient[entity.Member, *entity.CreateMemberOpts, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.MEMBER, Collection: fmt.Sprintf("lbaas/%s/%s/%s", consts.POOLS, poolId, consts.MEMBERS),
+		Key: consts.MEMBER, PluralKey: consts.MEMBERS, Paging: LinksPaging})
+}
+
+func (s *Controller) Servers() *ServerClient {
+	return NewResourceClient[entity.Server, *entity.CreateInstanceOpts, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.SERVER, Collection: consts.SERVERS,
+		Key: consts.SERVER, PluralKey: consts.SERVERS, Paging: MarkerPaging})
+}
+
+// Images are not wrapped in an envelope by glance.
+func (s *Controller) Images() *ImageClient {
+	return NewResourceClient[entity.ImageMap, *entity.CreateImageOpts, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.Image, Collection: consts.Images,
+		Key: "", PluralKey: consts.Images, Paging: NextPaging})
+}
+
+func (s *Controller) Volumes() *VolumeClient {
+	return NewResourceClient[entity.Volume, entity.CreateUpdateOptions, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.VOLUME, Collection: consts.VOLUMES,
+		Key: consts.VOLUME, PluralKey: consts.VOLUMES, Paging: MarkerPaging, ProjectScoped: true})
+}
+
+func (s *Controller) Snapshots() *SnapshotClient {
+	return NewResourceClient[entity.Snapshot, entity.CreateUpdateOptions, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.SNAPSHOT, Collection: consts.SNAPSHOTS,
+		Key: consts.SNAPSHOT, PluralKey: consts.SNAPSHOTS, Paging: MarkerPaging, ProjectScoped: true})
+}
+
+func (s *Controller) VolumeTypes() *VolumeTypeClient {
+	return NewResourceClient[entity.VolumeType, entity.CreateUpdateOptions, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.VOLUMETYPE, Collection: consts.VOLUMETYPES,
+		Key: "volume_type", PluralKey: "volume_types", Paging: MarkerPaging, ProjectScoped: true})
+}
+
+func (s *Controller) QosSpecs() *QosSpecsClient {
+	return NewResourceClient[entity.QosSpecs, entity.CreateUpdateOptions, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.QOSSPEC, Collection: "qos-specs",
+		Key: consts.QOSSPECS, PluralKey: consts.QOSSPECS, Paging: MarkerPaging, ProjectScoped: true})
+}
+
+// CinderAttachments are the attachments of the cinder attachment API, the
+// nova side of a volume attachment is VolumeAttachments.
+func (s *Controller) CinderAttachments() *CinderAttachmentClient {
+	return NewResourceClient[entity.Attachment, entity.CreateUpdateOptions, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.ATTACHMENT, Collection: "attachments",
+		Key: consts.ATTACHMENT, PluralKey: "attachments", Paging: MarkerPaging, ProjectScoped: true})
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/valyala/fasthttp"
+	"go-openstackclient/consts"
+	"go-openstackclient/internal/client"
+	"go-openstackclient/internal/entity"
+	"log"
+	"time"
+)
+
+// serverAction posts body to /servers/{id}/action and returns the response
+// body, most actions answer 202 without one.
+func (s *Controller) serverAction(ctx context.Context, serverId string, body entity.CreateUpdateOptions) ([]byte, error) {
+	return s.serverActionAt(ctx, serverId, "", body)
+}
+
+// serverActionAt is serverAction pinned to a compute microversion, the
+// default one of Nova when it is empty.
+func (s *Controller) serverActionAt(ctx context.Context, serverId, microversion string, body entity.CreateUpdateOptions) ([]byte, error) {
+	headers := make(map[string]string)
+	if microversion != "" {
+		headers[consts.OpenStackAPIVersion] = "compute " + microversion
+	}
+	resp, err := s.Do(ctx, RequestOption{
+		Action: CREATE,
+		Resource: consts.SERVER,
+		ResourceLocation: fmt.Sprintf("%s/%s/action", consts.SERVERS, serverId),
+		Body: body,
+		Headers: headers,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer fasthttp.ReleaseResponse(resp)
+	return append([]byte(nil), resp.Body()...), nil
+}
+
+// waitServerStatus waits until the server reaches one of statuses with no
+// task in progress, failing as soon as it goes to ERROR.
+func (s *Controller) waitServerStatus(ctx context.Context, serverId string, timeout time.Duration, statuses ...string) error {
+	description := fmt.Sprintf("server %s to be %v", serverId, statuses)
+	return waitFor(ctx, description, consts.IntervalTime, timeout, func() (bool, error) {
+		server, err := s.GetInstanceDetail(ctx, serverId)
+		if err != nil {
+			return false, err
+		}
+		if server.Status == consts.ERROR {
+			return false, fmt.Errorf("server %s went to ERROR", serverId)
+		}
+		if server.OSEXTSTSTaskState != nil {
+			return false, nil
+		}
+		for _, status := range statuses {
+			if server.Status == status {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+// doServerAction runs the action and waits for one of statuses.
+func (s *Controller) doServerAction(ctx context.Context, name, serverId string, body entity.CreateUpdateOptions, timeout time.Duration, statuses ...string) error {
+	if _, err := s.serverAction(ctx, serverId, body); err != nil {
+		return err
+	}
+	if err := s.waitServerStatus(ctx, serverId, timeout, statuses...); err != nil {
+		return err
+	}
+	log.Printf("==============%s server success %s", name, serverId)
+	return nil
+}
+
+// RebootServer reboots the server, a hard reboot power cycles it.
+func (s *Controller) RebootServer(ctx context.Context, serverId string, hard bool) error {
+	rebootType := "SOFT"
+	if hard {
+		rebootType = "HARD"
+	}
+	return s.doServerAction(ctx, "Reboot", serverId, &entity.RebootOpts{Type: rebootType}, consts.Timeout, consts.ACTIVE)
+}
+
+func (s *Controller) StopServer(ctx context.Context, serverId string) error {
+	return s.doServerAction(ctx, "Stop", serverId, entity.StopAction, consts.Timeout, consts.SHUTOFF)
+}
+
+func (s *Controller) StartServer(ctx context.Context, serverId string) error {
+	return s.doServerAction(ctx, "Start", serverId, entity.StartAction, consts.Timeout, consts.ACTIVE)
+}
+
+// ResizeServer moves the server to flavorId and waits for VERIFY_RESIZE, the
+// resize is then kept with ConfirmResizeServer or undone with
+// RevertResizeServer.
+func (s *Controller) ResizeServer(ctx context.Context, serverId, flavorId string) error {
+	return s.doServerAction(ctx, "Resize", serverId, &entity.ResizeOpts{FlavorRef: flavorId},
+		consts.MigrationTimeout, consts.VERIFYRESIZE)
+}
+
+// ConfirmResizeServer confirms a resize or a cold migration, the server goes
+// back to the state it had before, ACTIVE or SHUTOFF.
+func (s *Controller) ConfirmResizeServer(ctx context.Context, serverId string) error {
+	return s.doServerAction(ctx, "Confirm resize", serverId, entity.ConfirmResizeAction,
+		consts.Timeout, consts.ACTIVE, consts.SHUTOFF)
+}
+
+func (s *Controller) RevertResizeServer(ctx context.Context, serverId string) error {
+	return s.doServerAction(ctx, "Revert resize", serverId, entity.RevertResizeAction,
+		consts.MigrationTimeout, consts.ACTIVE, consts.SHUTOFF)
+}
+
+func (s *Controller) RebuildServer(ctx context.Context, serverId string, opts *entity.RebuildOpts) error {
+	return s.doServerAction(ctx, "Rebuild", serverId, opts, consts.MigrationTimeout, consts.ACTIVE, consts.SHUTOFF)
+}
+
+// ShelveServer shelves the server, it stays SHELVED when the cloud keeps
+// shelved servers on their host and becomes SHELVED_OFFLOADED otherwise.
+func (s *Controller) ShelveServer(ctx context.Context, serverId string) error {
+	return s.doServerAction(ctx, "Shelve", serverId, entity.ShelveAction,
+		consts.MigrationTimeout, consts.SHELVED, consts.SHELVEDOFFLOADED)
+}
+
+func (s *Controller) ShelveOffloadServer(ctx context.Context, serverId string) error {
+	return s.doServerAction(ctx, "Shelve offload", serverId, entity.ShelveOffloadAction,
+		consts.MigrationTimeout, consts.SHELVEDOFFLOADED)
+}
+
+func (s *Controller) UnshelveServer(ctx context.Context, serverId string) error {
+	return s.doServerAction(ctx, "Unshelve", serverId, entity.UnshelveAction, consts.MigrationTimeout, consts.ACTIVE)
+}
+
+// RescueServer boots the server from the rescue image and returns the
+// password of the rescue system.
+func (s *Controller) RescueServer(ctx context.Context, serverId string, opts *entity.RescueOpts) (string, error) {
+	body, err := s.serverAction(ctx, serverId, opts)
+	if err != nil {
+		return "", err
+	}
+	var rescue struct {
+		AdminPass     string `json:"adminPass"`
+	}
+	if len(body) != 0 {
+		if err = json.Unmarshal(body, &rescue); err != nil {
+			return "", err
+		}
+	}
+	if err = s.waitServerStatus(ctx, serverId, consts.Timeout, consts.RESCUE); err != nil {
+		return rescue.AdminPass, err
+	}
+	log.Println("==============Rescue server success", serverId)
+	return rescue.AdminPass, nil
+}
+
+func (s *Controller) UnrescueServer(ctx context.Context, serverId string) error {
+	return s.doServerAction(ctx, "Unrescue", serverId, entity.UnrescueAction, consts.Timeout, consts.ACTIVE)
+}
+
+// MigrateServer cold migrates the server to host, any host when it is
+// empty, and waits for VERIFY_RESIZE like a resize. With confirm the
+// migration is confirmed right away.
+func (s *Controller) MigrateServer(ctx context.Context, serverId, host string, confirm bool) error {
+	if err := s.doServerAction(ctx, "Migrate", serverId, &entity.MigrateOpts{Host: host},
+		consts.MigrationTimeout, consts.VERIFYRESIZE); err != nil {
+		return err
+	}
+	if !confirm {
+		return nil
+	}
+	if err := s.ConfirmResizeServer(ctx, serverId); err != nil {
+		return err
+	}
+	return s.checkServerHost(ctx, serverId, host)
+}
+
+// LiveMigrateServer moves the running server to host, where the scheduler
+// decides when host is empty, and checks it landed there.
+func (s *Controller) LiveMigrateServer(ctx context.Context, serverId, host string, blockMigration interface{}) error {
+	opts := &entity.LiveMigrateOpts{BlockMigration: blockMigration}
+	if host != "" {
+		opts.Host = &host
+	}
+	if err := s.doServerAction(ctx, "Live migrate", serverId, opts, consts.MigrationTimeout, consts.ACTIVE); err != nil {
+		return err
+	}
+	return s.checkServerHost(ctx, serverId, host)
+}
+
+// checkServerHost fails when a migration targeting host left the server
+// elsewhere, nova reports a failed migration only in its migration list.
+func (s *Controller) checkServerHost(ctx context.Context, serverId, host string) error {
+	if host == "" {
+		return nil
+	}
+	server, err := s.GetInstanceDetail(ctx, serverId)
+	if err != nil {
+		return err
+	}
+	if server.OSEXTSRVATTRHost != host {
+		return fmt.Errorf("server %s is on host %s after migrating to %s", serverId, server.OSEXTSRVATTRHost, host)
+	}
+	return nil
+}
+
+func (s *Controller) DeleteServer(ctx context.Context, serverId string) Output {
+	outputObj := Output{ParametersMap: map[string]string{"server_id": serverId}}
+	resp, err := s.Do(ctx, RequestOption{
+		Action: DELETE,
+		Resource: consts.SERVER,
+		ResourceLocation: fmt.Sprintf("%s/%s", consts.SERVERS, serverId),
+		Headers: make(map[string]string),
+	})
+	if err == nil {
+		fasthttp.ReleaseResponse(resp)
+		err = s.waitServerDeleted(ctx, serverId)
+	}
+	outputObj = resultOutput(outputObj, err)
+	if outputObj.Success {
+		log.Println("==============Delete server success", serverId)
+	}
+	return outputObj
+}
+
+// waitServerDeleted waits for the server to be gone, its ports and volumes
+// are released only then.
+func (s *Controller) waitServerDeleted(ctx context.Context, serverId string) error {
+	return waitFor(ctx, "server "+serverId+" to be deleted", consts.IntervalTime, consts.Timeout, func() (bool, error) {
+		server, err := s.GetInstanceDetail(ctx, serverId)
+		if client.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if server.Status == consts.ERROR && server.OSEXTSTSTaskState == nil {
+			return false, fmt.Errorf("server %s went to ERROR while deleting", serverId)
+		}
+		return false, nil
+	})
+}
+
+// DeleteServers is called by the Cleaner.
+func (s *Controller) DeleteServers(ctx context.Context) error {
+	servers, err := s.ListServers(ctx)
+	if err != nil {
+		return err
+	}
+	ch := s.MakeDeleteChannel(consts.SERVER, len(servers.Servers))
+	for _, server := range servers.Servers {
+		tempServer := server
+		go func() {
+			ch <- s.DeleteServer(ctx, tempServer.Id)
+		}()
+	}
+	if err := waitDeleteChannel(ctx, ch); err != nil {
+		return err
+	}
+	log.Println("Servers were deleted completely")
+	return nil
+}
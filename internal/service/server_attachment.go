@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-openstackclient/consts"
+	"go-openstackclient/internal/client"
+	"go-openstackclient/internal/entity"
+	"log"
+)
+
+type VolumeAttachmentClient = ResourceClient[entity.VolumeAttachment, *entity.CreateVolumeAttachmentOpts, entity.CreateUpdateOptions]
+
+type InterfaceAttachmentClient = ResourceClient[entity.InterfaceAttachment, *entity.CreateInterfaceAttachmentOpts, entity.CreateUpdateOptions]
+
+// VolumeAttachments are the volumes of the server serverId, keyed by volume
+// id.
+func (s *Controller) VolumeAttachments(serverId string) *VolumeAttachmentClient {
+	return NewResourceClient[entity.VolumeAttachment, *entity.CreateVolumeAttachmentOpts, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.SERVER, Collection: fmt.Sprintf("%s/%s/%s", consts.SERVERS, serverId, consts.OSVOLUMEATTACHMENTS),
+		Key: consts.VOLUMEATTACHMENT, PluralKey: consts.VOLUMEATTACHMENTS, Paging: MarkerPaging})
+}
+
+// InterfaceAttachments are the ports of the server serverId, keyed by port
+// id.
+func (s *Controller) InterfaceAttachments(serverId string) *InterfaceAttachmentClient {
+	return NewResourceClient[entity.InterfaceAttachment, *entity.CreateInterfaceAttachmentOpts, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.SERVER, Collection: fmt.Sprintf("%s/%s/%s", consts.SERVERS, serverId, consts.OSINTERFACE),
+		Key: consts.INTERFACEATTACHMENT, PluralKey: consts.INTERFACEATTACHMENTS, Paging: MarkerPaging})
+}
+
+// AttachVolume attaches the volume to the server and waits for it to be
+// in-use, device is left to nova when it is empty.
+func (s *Controller) AttachVolume(ctx context.Context, serverId, volumeId, device string) (entity.VolumeAttachment, error) {
+	attachment, err := s.VolumeAttachments(serverId).Create(ctx, &entity.CreateVolumeAttachmentOpts{
+		VolumeId: volumeId, Device: device})
+	if err != nil {
+		return attachment, err
+	}
+	if err = s.waitVolumeAttached(ctx, volumeId, serverId, true); err != nil {
+		return attachment, err
+	}
+	log.Println("==============Attach volume success", serverId, volumeId)
+	return attachment, nil
+}
+
+// DetachVolume detaches the volume from the server and waits until cinder no
+// longer lists the attachment, a multiattach volume stays in-use while other
+// servers have it.
+func (s *Controller) DetachVolume(ctx context.Context, serverId, volumeId string) error {
+	if err := s.VolumeAttachments(serverId).Delete(ctx, volumeId); err != nil {
+		return err
+	}
+	if err := s.waitVolumeAttached(ctx, volumeId, serverId, false); err != nil {
+		return err
+	}
+	log.Println("==============Detach volume success", serverId, volumeId)
+	return nil
+}
+
+func (s *Controller) ListVolumeAttachments(ctx context.Context, serverId string) ([]entity.VolumeAttachment, error) {
+	return s.VolumeAttachments(serverId).List(ctx, "")
+}
+
+// waitVolumeAttached waits for the volume to settle in-use with an
+// attachment to the server, or available or in-use without one when
+// attached is false.
+func (s *Controller) waitVolumeAttached(ctx context.Context, volumeId, serverId string, attached bool) error {
+	description := fmt.Sprintf("volume %s to be detached from server %s", volumeId, serverId)
+	if attached {
+		description = fmt.Sprintf("volume %s to be attached to server %s", volumeId, serverId)
+	}
+	return waitFor(ctx, description, consts.IntervalTime, consts.Timeout, func() (bool, error) {
+		volume, err := s.GetVolume(ctx, volumeId)
+		if err != nil {
+			return false, err
+		}
+		if volume.Status == consts.Error || volume.Status == "error_attaching" || volume.Status == "error_detaching" {
+			return false, fmt.Errorf("volume %s went to %s", volumeId, volume.Status)
+		}
+		found := false
+		for _, attachment := range volume.Attachments {
+			if attachment.ServerId == serverId {
+				found = true
+			}
+		}
+		if attached {
+			return found && volume.Status == consts.InUse, nil
+		}
+		return !found && (volume.Status == consts.Available || volume.Status == consts.InUse), nil
+	})
+}
+
+// AttachInterface plugs a port into the server and waits for it to be
+// ACTIVE.
+func (s *Controller) AttachInterface(ctx context.Context, serverId string, opts *entity.CreateInterfaceAttachmentOpts) (entity.InterfaceAttachment, error) {
+	if (opts.PortId == "") == (opts.NetId == "") {
+		return entity.InterfaceAttachment{}, fmt.Errorf("attach interface needs either a port or a network")
+	}
+	attachment, err := s.InterfaceAttachments(serverId).Create(ctx, opts)
+	if err != nil {
+		return attachment, err
+	}
+	err = waitFor(ctx, "port "+attachment.PortId+" to become ACTIVE", consts.IntervalTime, consts.Timeout, func() (bool, error) {
+		port, err := s.Ports().Get(ctx, attachment.PortId)
+		if err != nil {
+			return false, err
+		}
+		return port.Status == consts.ACTIVE && port.DeviceId == serverId, nil
+	})
+	if err != nil {
+		return attachment, err
+	}
+	log.Println("==============Attach interface success", serverId, attachment.PortId)
+	return attachment, nil
+}
+
+// DetachInterface unplugs the port and waits for it to be DOWN and unbound,
+// or deleted when nova created it on attach.
+func (s *Controller) DetachInterface(ctx context.Context, serverId, portId string) error {
+	if err := s.InterfaceAttachments(serverId).Delete(ctx, portId); err != nil {
+		return err
+	}
+	err := waitFor(ctx, "port "+portId+" to be detached", consts.IntervalTime, consts.Timeout, func() (bool, error) {
+		port, err := s.Ports().Get(ctx, portId)
+		if client.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return port.Status == consts.DOWN && port.DeviceId != serverId, nil
+	})
+	if err != nil {
+		return err
+	}
+	log.Println("==============Detach interface success", serverId, portId)
+	return nil
+}
+
+func (s *Controller) ListInterfaces(ctx context.Context, serverId string) ([]entity.InterfaceAttachment, error) {
+	return s.InterfaceAttachments(serverId).List(ctx, "")
+}
+
+// detachVolumeAttachment detaches the volume through nova while the server
+// exists, nova would otherwise keep the block device mapping, and deletes
+// the cinder attachment of a server that is gone.
+func (s *Controller) detachVolumeAttachment(ctx context.Context, attachment entity.Attachment) error {
+	_, err := s.GetInstanceDetail(ctx, attachment.ServerId)
+	if client.IsNotFound(err) {
+		return s.DeleteAttachment(ctx, attachment.AttachmentId)
+	}
+	if err != nil {
+		return err
+	}
+	return s.DetachVolume(ctx, attachment.ServerId, attachment.VolumeId)
+}
+
+// detachVolumeAttachments detaches volume from every server it is attached
+// to and waits for it to be available again, whether nova or the cinder
+// attachment API did the detach; cinder refuses to delete a volume that is
+// still in-use or detaching.
+func (s *Controller) detachVolumeAttachments(ctx context.Context, volume entity.Volume) error {
+	if len(volume.Attachments) == 0 {
+		return nil
+	}
+	for _, attachment := range volume.Attachments {
+		if err := s.detachVolumeAttachment(ctx, attachment); err != nil {
+			log.Println("catch error：", err)
+		}
+	}
+	return s.MakeSureVolumeAvailable(ctx, volume.Id)
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-openstackclient/consts"
+	"go-openstackclient/internal/entity"
+	"log"
+)
+
+type ServerGroupClient = ResourceClient[entity.ServerGroup, *entity.CreateServerGroupOpts, entity.CreateUpdateOptions]
+
+// ServerGroups can't be updated, nova keeps the policy of a group for its
+// lifetime.
+func (s *Controller) ServerGroups() *ServerGroupClient {
+	return NewResourceClient[entity.ServerGroup, *entity.CreateServerGroupOpts, entity.CreateUpdateOptions](s, ResourceSpec{
+		Resource: consts.SERVERGROUP, Collection: consts.OSSERVERGROUPS,
+		Key: consts.SERVERGROUP, PluralKey: consts.SERVERGROUPS, Paging: MarkerPaging})
+}
+
+var serverGroupPolicies = map[string]struct{}{
+	"affinity": {}, "anti-affinity": {}, "soft-affinity": {}, "soft-anti-affinity": {},
+}
+
+// CreateServerGroup creates a group of policy, max_server_per_host is only
+// allowed with anti-affinity.
+func (s *Controller) CreateServerGroup(ctx context.Context, opts *entity.CreateServerGroupOpts) (entity.ServerGroup, error) {
+	if _, ok := serverGroupPolicies[opts.Policy]; !ok {
+		return entity.ServerGroup{}, fmt.Errorf("invalid server group policy %q", opts.Policy)
+	}
+	if opts.Rules != nil && opts.Rules.MaxServerPerHost != 0 && opts.Policy != "anti-affinity" {
+		return entity.ServerGroup{}, fmt.Errorf("max_server_per_host needs the anti-affinity policy, not %q", opts.Policy)
+	}
+	group, err := s.ServerGroups().Create(ctx, opts)
+	if err == nil {
+		log.Println("==============Create server group success", group.Id)
+	}
+	return group, err
+}
+
+func (s *Controller) GetServerGroup(ctx context.Context, groupId string) (entity.ServerGroup, error) {
+	return s.ServerGroups().Get(ctx, groupId)
+}
+
+// ListServerGroups lists the groups of the project, of every project with
+// allProjects.
+func (s *Controller) ListServerGroups(ctx context.Context, allProjects bool) ([]entity.ServerGroup, error) {
+	query := ""
+	if allProjects {
+		query = "all_projects=true"
+	}
+	groups, err := s.ServerGroups().List(ctx, query)
+	if err == nil {
+		log.Println("==============List server groups success, there had", len(groups))
+	}
+	return groups, err
+}
+
+// FindServerGroup returns the group of the project named name, nova can't
+// filter the list by name.
+func (s *Controller) FindServerGroup(ctx context.Context, name string) (entity.ServerGroup, error) {
+	groups, err := s.ListServerGroups(ctx, false)
+	if err != nil {
+		return entity.ServerGroup{}, err
+	}
+	found := make([]entity.ServerGroup, 0, 1)
+	for _, group := range groups {
+		if group.Name == name || group.Id == name {
+			found = append(found, group)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return entity.ServerGroup{}, fmt.Errorf("%s %q: %w", consts.SERVERGROUP, name, ErrNotFound)
+	case 1:
+		return found[0], nil
+	default:
+		return entity.ServerGroup{}, fmt.Errorf("found %d %s named %q, use the id instead", len(found), consts.SERVERGROUPS, name)
+	}
+}
+
+func (s *Controller) DeleteServerGroup(ctx context.Context, groupId string) error {
+	err := s.ServerGroups().Delete(ctx, groupId)
+	if err == nil {
+		log.Println("==============Delete server group success", groupId)
+	}
+	return err
+}
@@ -1,13 +1,124 @@
 package service
 
 import (
+	"context"
+	"fmt"
 	"github.com/valyala/fasthttp"
+	"go-openstackclient/configs"
+	"go-openstackclient/consts"
 	"go-openstackclient/internal/client"
+	"sort"
 )
 
 type Service interface {
 	HttpPrefix()                  string
 	SupportedResources()          map[string]struct{}
-	Call(req client.Request)      *fasthttp.Response
+	Call(ctx context.Context, req client.Request) (*fasthttp.Response, error)
 	Client()                      client.Client
 }
+
+// ServiceFactory builds a service for the profile and catalog of a controller.
+type ServiceFactory func(conf *configs.Openstack, catalog *Catalog, opts ...client.ClientOption) Service
+
+// ServiceSpec declares a service and the resource types requests for which
+// are sent to it.
+type ServiceSpec struct {
+	Name          string
+	Resources     map[string]struct{}
+	New           ServiceFactory
+}
+
+// ServiceRegistry routes every resource type to exactly one service.
+type ServiceRegistry struct {
+	specs         map[string]ServiceSpec
+	owners        map[string]string
+}
+
+// NewServiceRegistry registers specs in order, failing on the first one
+// that claims a resource type another service already serves.
+func NewServiceRegistry(specs ...ServiceSpec) (*ServiceRegistry, error) {
+	r := &ServiceRegistry{
+		specs: make(map[string]ServiceSpec),
+		owners: make(map[string]string),
+	}
+	for _, spec := range specs {
+		if err := r.Register(spec); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// DefaultServiceRegistry returns a registry of the neutron, keystone, nova,
+// cinder, octavia and glance services, ready to Register more.
+func DefaultServiceRegistry() (*ServiceRegistry, error) {
+	return NewServiceRegistry(
+		ServiceSpec{Name: consts.NEUTRON, Resources: supportedNeutronResourceTypes,
+			New: func(conf *configs.Openstack, catalog *Catalog, opts ...client.ClientOption) Service {
+				return newNeutron(conf, catalog, opts...)
+			}},
+		ServiceSpec{Name: consts.KEYSTONE, Resources: supportedKeystoneResourceTypes,
+			New: func(conf *configs.Openstack, catalog *Catalog, opts ...client.ClientOption) Service {
+				return newKeystone(conf, catalog, opts...)
+			}},
+		ServiceSpec{Name: consts.NOVA, Resources: supportedNovaResourceTypes,
+			New: func(conf *configs.Openstack, catalog *Catalog, opts ...client.ClientOption) Service {
+				return newNova(conf, catalog, opts...)
+			}},
+		ServiceSpec{Name: consts.CINDER, Resources: supportedCinderResourceTypes,
+			New: func(conf *configs.Openstack, catalog *Catalog, opts ...client.ClientOption) Service {
+				return newCinder(conf, catalog, opts...)
+			}},
+		ServiceSpec{Name: consts.OCTAVIA, Resources: supportedOctaviaResourceTypes,
+			New: func(conf *configs.Openstack, catalog *Catalog, opts ...client.ClientOption) Service {
+				return newOctavia(conf, catalog, opts...)
+			}},
+		ServiceSpec{Name: consts.GLANCE, Resources: supportedGlanceResourceTypes,
+			New: func(conf *configs.Openstack, catalog *Catalog, opts ...client.ClientOption) Service {
+				return newGlance(conf, catalog, opts...)
+			}},
+	)
+}
+
+// defaultServices backs controllers built without WithServiceRegistry, the
+// built-in services never overlap so this only fails on a programming error.
+var defaultServices = mustServiceRegistry(DefaultServiceRegistry())
+
+func mustServiceRegistry(r *ServiceRegistry, err error) *ServiceRegistry {
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Register adds spec, replacing nothing: a service name or resource type
+// that is already registered is an error.
+func (r *ServiceRegistry) Register(spec ServiceSpec) error {
+	if spec.Name == "" || spec.New == nil {
+		return fmt.Errorf("service spec needs a name and a factory")
+	}
+	if _, ok := r.specs[spec.Name]; ok {
+		return fmt.Errorf("service %q is already registered", spec.Name)
+	}
+	conflicts := make([]string, 0)
+	for resource := range spec.Resources {
+		if owner, ok := r.owners[resource]; ok {
+			conflicts = append(conflicts, fmt.Sprintf("%q (served by %s)", resource, owner))
+		}
+	}
+	if len(conflicts) != 0 {
+		sort.Strings(conflicts)
+		return fmt.Errorf("service %s claims resource types already registered: %v", spec.Name, conflicts)
+	}
+	r.specs[spec.Name] = spec
+	for resource := range spec.Resources {
+		r.owners[resource] = spec.Name
+	}
+	return nil
+}
+
+// ServiceFor returns the name of the service serving resourceType.
+func (r *ServiceRegistry) ServiceFor(resourceType string) (string, bool) {
+	name, ok := r.owners[resourceType]
+	return name, ok
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-openstackclient/consts"
+	"go-openstackclient/internal/client"
+	"go-openstackclient/internal/entity"
+	"log"
+	"net"
+	"strings"
+)
+
+// vpcSide is the router and the networks of one side of a vpc connection.
+type vpcSide struct {
+	Router            string
+	Cidrs             []string
+	Subnets           []string
+}
+
+// CreateVpcConnection checks both sides with checkVpcConnectionSides, then
+// waits for the connection to be ACTIVE.
+func (s *Controller) CreateVpcConnection(ctx context.Context, opts *entity.CreateVpcConnectionOpts) (entity.VpcConnection, error) {
+	if err := s.checkVpcConnectionSides(ctx,
+		vpcSide{Router: opts.LocalRouter, Cidrs: opts.LocalCidrs, Subnets: opts.LocalSubnets},
+		vpcSide{Router: opts.PeerRouter, Cidrs: opts.PeerCidrs, Subnets: opts.PeerSubnets}); err != nil {
+		return entity.VpcConnection{}, err
+	}
+	connection, err := s.VpcConnections().Create(ctx, opts)
+	if err != nil {
+		return connection, err
+	}
+	if err = s.makeSureVpcConnectionActive(ctx, connection.Id); err != nil {
+		return connection, err
+	}
+	return s.GetVpcConnection(ctx, connection.Id)
+}
+
+// UpdateVpcConnection merges the update into the current connection field
+// by field and validates the resulting sides as CreateVpcConnection does,
+// then waits for the connection to be ACTIVE again.
+func (s *Controller) UpdateVpcConnection(ctx context.Context, connectionId string, opts *entity.UpdateVpcConnectionOpts) (entity.VpcConnection, error) {
+	current, err := s.GetVpcConnection(ctx, connectionId)
+	if err != nil {
+		return current, err
+	}
+	local, peer := mergeVpcSides(opts, current)
+	if err = s.checkVpcConnectionSides(ctx, local, peer); err != nil {
+		return current, err
+	}
+
+	if _, err = s.VpcConnections().Update(ctx, connectionId, opts); err != nil {
+		return current, err
+	}
+	if err = s.makeSureVpcConnectionActive(ctx, connectionId); err != nil {
+		return current, err
+	}
+	return s.GetVpcConnection(ctx, connectionId)
+}
+
+// mergeVpcSides returns the sides current has once opts is applied.
+func mergeVpcSides(opts *entity.UpdateVpcConnectionOpts, current entity.VpcConnection) (vpcSide, vpcSide) {
+	local := vpcSide{
+		Router: mergeString(opts.LocalRouter, current.LocalRouter),
+		Cidrs: mergeStrings(opts.LocalCidrs, current.LocalCidrs),
+		Subnets: mergeStrings(opts.LocalSubnets, current.LocalSubnets)}
+	peer := vpcSide{
+		Router: mergeString(opts.PeerRouter, current.PeerRouter),
+		Cidrs: mergeStrings(opts.PeerCidrs, current.PeerCidrs),
+		Subnets: mergeStrings(opts.PeerSubnets, current.PeerSubnets)}
+	return local, peer
+}
+
+// mergeString is the updated value, or the current one when the update
+// leaves it out.
+func mergeString(updated, current string) string {
+	if updated == "" {
+		return current
+	}
+	return updated
+}
+
+// mergeStrings is mergeString for lists, an empty list is omitted from the
+// update body and so keeps the current one.
+func mergeStrings(updated, current []string) []string {
+	if len(updated) == 0 {
+		return current
+	}
+	return updated
+}
+
+func (s *Controller) GetVpcConnection(ctx context.Context, connectionId string) (entity.VpcConnection, error) {
+	connection, err := s.VpcConnections().Get(ctx, connectionId)
+	if err == nil {
+		log.Println("==============Get vpc connection success", connectionId)
+	}
+	return connection, err
+}
+
+func (s *Controller) ListVpcConnections(ctx context.Context, opts ...ListOption) ([]entity.VpcConnection, error) {
+	query, err := s.projectFilter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	connections, err := s.VpcConnections().List(ctx, query, opts...)
+	if err != nil {
+		return connections, err
+	}
+	log.Println("==============List vpc connections success, there had", len(connections))
+	return connections, nil
+}
+
+func (s *Controller) DeleteVpcConnection(ctx context.Context, connectionId string) Output {
+	outputObj := Output{ParametersMap: map[string]string{"vpc_connection_id": connectionId}}
+	return resultOutput(outputObj, s.VpcConnections().Delete(ctx, connectionId))
+}
+
+// DeleteVpcConnections is called by the Cleaner.
+func (s *Controller) DeleteVpcConnections(ctx context.Context) error {
+	return s.VpcConnections().DeleteAll(ctx)
+}
+
+func (s *Controller) makeSureVpcConnectionActive(ctx context.Context, connectionId string) error {
+	return waitFor(ctx, "vpc connection "+connectionId+" to be ACTIVE", consts.IntervalTime, consts.Timeout, func() (bool, error) {
+		connection, err := s.VpcConnections().Get(ctx, connectionId)
+		if err != nil {
+			return false, err
+		}
+		if strings.ToUpper(connection.Status) == "ERROR" {
+			return false, fmt.Errorf("vpc connection %s is in ERROR", connectionId)
+		}
+		return connection.Status == consts.ACTIVE, nil
+	})
+}
+
+func (s *Controller) checkRouterExist(ctx context.Context, routerId string) error {
+	if routerId == "" {
+		return fmt.Errorf("vpc connection needs a local and a peer router")
+	}
+	if _, err := s.Routers().Get(ctx, routerId); err != nil {
+		if client.IsNotFound(err) {
+			return fmt.Errorf("router %s: %w", routerId, ErrNotFound)
+		}
+		return err
+	}
+	return nil
+}
+
+// checkVpcConnectionSides checks that the router of each side exists and
+// has the subnets of its side attached, and that no local network overlaps
+// a peer one, the connection could not route between them.
+func (s *Controller) checkVpcConnectionSides(ctx context.Context, local, peer vpcSide) error {
+	for _, side := range []vpcSide{local, peer} {
+		if err := s.checkRouterExist(ctx, side.Router); err != nil {
+			return err
+		}
+		if err := s.checkRouterSubnets(ctx, side.Router, side.Subnets); err != nil {
+			return err
+		}
+	}
+	localNetworks, err := s.vpcSideNetworks(ctx, local.Cidrs, local.Subnets)
+	if err != nil {
+		return err
+	}
+	peerNetworks, err := s.vpcSideNetworks(ctx, peer.Cidrs, peer.Subnets)
+	if err != nil {
+		return err
+	}
+	return checkNetworksOverlap(localNetworks, peerNetworks)
+}
+
+// checkNetworksOverlap fails when one of the local networks overlaps one of
+// the peer networks.
+func checkNetworksOverlap(localNetworks, peerNetworks []*net.IPNet) error {
+	for _, l := range localNetworks {
+		for _, p := range peerNetworks {
+			if l.Contains(p.IP) || p.Contains(l.IP) {
+				return fmt.Errorf("local cidr %s overlaps peer cidr %s", l, p)
+			}
+		}
+	}
+	return nil
+}
+
+// checkRouterSubnets fails when one of subnets is not attached to an
+// interface of the router.
+func (s *Controller) checkRouterSubnets(ctx context.Context, routerId string, subnets []string) error {
+	if len(subnets) == 0 {
+		return nil
+	}
+	ports, err := s.Ports().List(ctx, "device_id="+routerId)
+	if err != nil {
+		return err
+	}
+	attached := make(map[string]struct{})
+	for _, port := range ports {
+		if !strings.HasPrefix(port.DeviceOwner, consts.NETWORKROUTERINTERFACE) {
+			continue
+		}
+		for _, fixedIp := range port.FixedIps {
+			attached[fixedIp.SubnetId] = struct{}{}
+		}
+	}
+	for _, subnetId := range subnets {
+		if _, ok := attached[subnetId]; !ok {
+			return fmt.Errorf("subnet %s is not attached to router %s", subnetId, routerId)
+		}
+	}
+	return nil
+}
+
+// vpcSideNetworks returns the networks of cidrs and of the cidrs of subnets.
+func (s *Controller) vpcSideNetworks(ctx context.Context, cidrs, subnets []string) ([]*net.IPNet, error) {
+	all := append([]string{}, cidrs...)
+	for _, subnetId := range subnets {
+		subnet, err := s.Subnets().Get(ctx, subnetId)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, subnet.Cidr)
+	}
+	networks := make([]*net.IPNet, 0, len(all))
+	for _, cidr := range all {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cidr %q: %w", cidr, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
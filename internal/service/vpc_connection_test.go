@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"go-openstackclient/internal/entity"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestMergeString(t *testing.T) {
+	tests := []struct {
+		updated  string
+		current  string
+		want     string
+	}{
+		{"r2", "r1", "r2"},
+		{"", "r1", "r1"},
+		{"r2", "", "r2"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		if got := mergeString(tt.updated, tt.current); got != tt.want {
+			t.Errorf("mergeString(%q, %q) = %q, want %q", tt.updated, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestMergeVpcSides(t *testing.T) {
+	current := entity.VpcConnection{
+		LocalRouter: "r1", LocalCidrs: []string{"10.0.0.0/24"}, LocalSubnets: []string{"s1"},
+		PeerRouter: "r2", PeerCidrs: []string{"10.1.0.0/24"}, PeerSubnets: []string{"s2"},
+	}
+	tests := []struct {
+		name       string
+		opts       entity.UpdateVpcConnectionOpts
+		wantLocal  vpcSide
+		wantPeer   vpcSide
+	}{
+		{
+			name: "empty update",
+			wantLocal: vpcSide{Router: "r1", Cidrs: []string{"10.0.0.0/24"}, Subnets: []string{"s1"}},
+			wantPeer: vpcSide{Router: "r2", Cidrs: []string{"10.1.0.0/24"}, Subnets: []string{"s2"}},
+		},
+		{
+			name: "peer cidrs",
+			opts: entity.UpdateVpcConnectionOpts{PeerCidrs: []string{"10.2.0.0/24"}},
+			wantLocal: vpcSide{Router: "r1", Cidrs: []string{"10.0.0.0/24"}, Subnets: []string{"s1"}},
+			wantPeer: vpcSide{Router: "r2", Cidrs: []string{"10.2.0.0/24"}, Subnets: []string{"s2"}},
+		},
+		{
+			name: "local router and subnets",
+			opts: entity.UpdateVpcConnectionOpts{LocalRouter: "r3", LocalSubnets: []string{"s3", "s4"}},
+			wantLocal: vpcSide{Router: "r3", Cidrs: []string{"10.0.0.0/24"}, Subnets: []string{"s3", "s4"}},
+			wantPeer: vpcSide{Router: "r2", Cidrs: []string{"10.1.0.0/24"}, Subnets: []string{"s2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			local, peer := mergeVpcSides(&tt.opts, current)
+			if !reflect.DeepEqual(local, tt.wantLocal) || !reflect.DeepEqual(peer, tt.wantPeer) {
+				t.Errorf("mergeVpcSides() = %+v, %+v, want %+v, %+v", local, peer, tt.wantLocal, tt.wantPeer)
+			}
+		})
+	}
+}
+
+func TestCheckNetworksOverlap(t *testing.T) {
+	tests := []struct {
+		name   string
+		local  []string
+		peer   []string
+		err    bool
+	}{
+		{"disjoint", []string{"10.0.0.0/24"}, []string{"10.0.1.0/24"}, false},
+		{"equal", []string{"10.0.0.0/24"}, []string{"10.0.0.0/24"}, true},
+		{"local within peer", []string{"10.0.0.0/24"}, []string{"10.0.0.0/16"}, true},
+		{"peer within local", []string{"192.168.0.0/24", "10.0.0.0/16"}, []string{"10.0.5.0/24"}, true},
+		{"no peer networks", []string{"10.0.0.0/24"}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkNetworksOverlap(parseNetworks(t, tt.local), parseNetworks(t, tt.peer))
+			if (err != nil) != tt.err {
+				t.Errorf("checkNetworksOverlap(%v, %v) = %v, want error %v", tt.local, tt.peer, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestCheckVpcConnectionSidesNeedsRouters(t *testing.T) {
+	s := NewController(defaultName)
+	local := vpcSide{Cidrs: []string{"10.0.0.0/24"}}
+	peer := vpcSide{Cidrs: []string{"10.1.0.0/24"}}
+	if err := s.checkVpcConnectionSides(context.Background(), local, peer); err == nil {
+		t.Error("checkVpcConnectionSides() accepted sides without routers")
+	}
+}
+
+func parseNetworks(t *testing.T, cidrs []string) []*net.IPNet {
+	t.Helper()
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-openstackclient/consts"
+	"go-openstackclient/internal/entity"
+	"log"
+	"strings"
+)
+
+type (
+	VpnServiceClient          = ResourceClient[entity.VpnService, *entity.CreateVpnServiceOpts, *entity.UpdateVpnServiceOpts]
+	EndpointGroupClient       = ResourceClient[entity.EndpointGroup, *entity.CreateEndpointGroupOpts, *entity.UpdateEndpointGroupOpts]
+	IkePolicyClient           = ResourceClient[entity.Ikepolicy, *entity.CreateIkePolicyOpts, *entity.UpdateIkePolicyOpts]
+	IpsecPolicyClient         = ResourceClient[entity.Ipsecpolicy, *entity.CreateIpsecPolicyOpts, *entity.UpdateIpsecPolicyOpts]
+	IpsecSiteConnectionClient = ResourceClient[entity.IpsecSiteConnection, *entity.CreateIpsecSiteConnectionOpts, *entity.UpdateIpsecSiteConnectionOpts]
+)
+
+func (s *Controller) VpnServices() *VpnServiceClient {
+	return NewResourceClient[entity.VpnService, *entity.CreateVpnServiceOpts, *entity.UpdateVpnServiceOpts](s, ResourceSpec{
+		Resource: consts.VPNSERVICE, Collection: "vpn/" + consts.VPNSERVICES,
+		Key: consts.VPNSERVICE, PluralKey: consts.VPNSERVICES, Paging: LinksPaging})
+}
+
+func (s *Controller) EndpointGroups() *EndpointGroupClient {
+	return NewResourceClient[entity.EndpointGroup, *entity.CreateEndpointGroupOpts, *entity.UpdateEndpointGroupOpts](s, ResourceSpec{
+		Resource: consts.ENDPOINTGROUP, Collection: "vpn/endpoint-groups",
+		Key: consts.ENDPOINTGROUP, PluralKey: consts.ENDPOINTGROUPS, Paging: LinksPaging})
+}
+
+func (s *Controller) IkePolicies() *IkePolicyClient {
+	return NewResourceClient[entity.Ikepolicy, *entity.CreateIkePolicyOpts, *entity.UpdateIkePolicyOpts](s, ResourceSpec{
+		Resource: consts.IKEPOLICY, Collection: "vpn/" + consts.IKEPOLICIES,
+		Key: consts.IKEPOLICY, PluralKey: consts.IKEPOLICIES, Paging: LinksPaging})
+}
+
+func (s *Controller) IpsecPolicies() *IpsecPolicyClient {
+	return NewResourceClient[entity.Ipsecpolicy, *entity.CreateIpsecPolicyOpts, *entity.UpdateIpsecPolicyOpts](s, ResourceSpec{
+		Resource: consts.IPSECPOLICY, Collection: "vpn/" + consts.IPSECPOLICIES,
+		Key: consts.IPSECPOLICY, PluralKey: consts.IPSECPOLICIES, Paging: LinksPaging})
+}
+
+func (s *Controller) IpsecSiteConnections() *IpsecSiteConnectionClient {
+	return NewResourceClient[entity.IpsecSiteConnection, *entity.CreateIpsecSiteConnectionOpts, *entity.UpdateIpsecSiteConnectionOpts](s, ResourceSpec{
+		Resource: consts.IPSECCONNECTION, Collection: "vpn/ipsec-site-connections",
+		Key: consts.IPSECCONNECTION, PluralKey: consts.IPSECCONNECTIONS, Paging: LinksPaging})
+}
+
+// The Delete<Resources> methods below are called by the Cleaner, their
+// names follow utils.Pluralize of the resource types.
+
+func (s *Controller) DeleteVpnservices(ctx context.Context) error {
+	return s.VpnServices().DeleteAll(ctx)
+}
+
+func (s *Controller) DeleteEndpointGroups(ctx context.Context) error {
+	return s.EndpointGroups().DeleteAll(ctx)
+}
+
+func (s *Controller) DeleteIkepolicies(ctx context.Context) error {
+	return s.IkePolicies().DeleteAll(ctx)
+}
+
+func (s *Controller) DeleteIpsecpolicies(ctx context.Context) error {
+	return s.IpsecPolicies().DeleteAll(ctx)
+}
+
+func (s *Controller) DeleteIpsecSiteConnections(ctx context.Context) error {
+	return s.IpsecSiteConnections().DeleteAll(ctx)
+}
+
+// makeSureIpsecConnectionActive waits for both ends to negotiate, the
+// connection stays DOWN until its peer is up.
+func (s *Controller) makeSureIpsecConnectionActive(ctx context.Context, connectionId string) error {
+	return waitFor(ctx, "ipsec site connection "+connectionId+" to be ACTIVE", consts.IntervalTime, consts.Timeout, func() (bool, error) {
+		connection, err := s.IpsecSiteConnections().Get(ctx, connectionId)
+		if err != nil {
+			return false, err
+		}
+		if strings.ToUpper(connection.Status) == "ERROR" {
+			return false, fmt.Errorf("ipsec site connection %s is in ERROR", connectionId)
+		}
+		return connection.Status == consts.ACTIVE, nil
+	})
+}
+
+// VPNSite is one end of a site-to-site VPN: the router terminating it and
+// the subnets behind it.
+type VPNSite struct {
+	RouterId          string
+	SubnetIds         []string
+}
+
+// SiteToSiteVPNOpts describes a VPN between the subnets of two routers. The
+// policies default to the neutron defaults when left empty.
+type SiteToSiteVPNOpts struct {
+	Name              string
+	Left              VPNSite
+	Right             VPNSite
+	PSK               string
+	IkePolicy         entity.CreateIkePolicyOpts
+	IpsecPolicy       entity.CreateIpsecPolicyOpts
+}
+
+// VPNEnd holds the resources created for one end of a site-to-site VPN.
+type VPNEnd struct {
+	VpnService            entity.VpnService
+	LocalEndpointGroup    entity.EndpointGroup
+	PeerEndpointGroup     entity.EndpointGroup
+	Connection            entity.IpsecSiteConnection
+}
+
+type SiteToSiteVPN struct {
+	IkePolicy         entity.Ikepolicy
+	IpsecPolicy       entity.Ipsecpolicy
+	Left              VPNEnd
+	Right             VPNEnd
+}
+
+// CreateSiteToSiteVPN wires opts.Left and opts.Right with one IKE and one
+// IPsec policy, a VPN service and a pair of endpoint groups per router, then
+// connects both ends and waits for the connections to be ACTIVE. On failure
+// the resources created so far are returned with the error so that the
+// caller can clean them up.
+func (s *Controller) CreateSiteToSiteVPN(ctx context.Context, opts SiteToSiteVPNOpts) (SiteToSiteVPN, error) {
+	var vpn SiteToSiteVPN
+	if opts.PSK == "" {
+		return vpn, fmt.Errorf("site-to-site VPN %s needs a pre-shared key", opts.Name)
+	}
+	if len(opts.Left.SubnetIds) == 0 || len(opts.Right.SubnetIds) == 0 {
+		return vpn, fmt.Errorf("site-to-site VPN %s needs subnets on both ends", opts.Name)
+	}
+
+	ikeOpts := opts.IkePolicy
+	if ikeOpts.Name == "" {
+		ikeOpts.Name = opts.Name + "-ike"
+	}
+	ikePolicy, err := s.IkePolicies().Create(ctx, &ikeOpts)
+	if err != nil {
+		return vpn, err
+	}
+	vpn.IkePolicy = ikePolicy
+
+	ipsecOpts := opts.IpsecPolicy
+	if ipsecOpts.Name == "" {
+		ipsecOpts.Name = opts.Name + "-ipsec"
+	}
+	ipsecPolicy, err := s.IpsecPolicies().Create(ctx, &ipsecOpts)
+	if err != nil {
+		return vpn, err
+	}
+	vpn.IpsecPolicy = ipsecPolicy
+
+	if vpn.Left, err = s.createVPNEnd(ctx, opts.Name+"-left", opts.Left, opts.Right); err != nil {
+		return vpn, err
+	}
+	if vpn.Right, err = s.createVPNEnd(ctx, opts.Name+"-right", opts.Right, opts.Left); err != nil {
+		return vpn, err
+	}
+
+	if vpn.Left.Connection, err = s.connectVPNEnd(ctx, opts.Name+"-left", vpn, vpn.Left, vpn.Right, opts.PSK); err != nil {
+		return vpn, err
+	}
+	if vpn.Right.Connection, err = s.connectVPNEnd(ctx, opts.Name+"-right", vpn, vpn.Right, vpn.Left, opts.PSK); err != nil {
+		return vpn, err
+	}
+
+	for _, connection := range []entity.IpsecSiteConnection{vpn.Left.Connection, vpn.Right.Connection} {
+		if err = s.makeSureIpsecConnectionActive(ctx, connection.Id); err != nil {
+			return vpn, err
+		}
+	}
+	log.Println("==============Create site-to-site vpn success", opts.Name)
+	return vpn, nil
+}
+
+// createVPNEnd creates the VPN service of local and the endpoint groups of
+// its subnets and of the CIDRs of the peer subnets.
+func (s *Controller) createVPNEnd(ctx context.Context, name string, local, peer VPNSite) (VPNEnd, error) {
+	var end VPNEnd
+	peerCidrs := make([]string, 0, len(peer.SubnetIds))
+	for _, subnetId := range peer.SubnetIds {
+		subnet, err := s.Subnets().Get(ctx, subnetId)
+		if err != nil {
+			return end, err
+		}
+		peerCidrs = append(peerCidrs, subnet.Cidr)
+	}
+
+	vpnService, err := s.VpnServices().Create(ctx, &entity.CreateVpnServiceOpts{
+		RouterID: local.RouterId, Name: name})
+	if err != nil {
+		return end, err
+	}
+	end.VpnService = vpnService
+
+	if end.LocalEndpointGroup, err = s.EndpointGroups().Create(ctx, &entity.CreateEndpointGroupOpts{
+		Type: "subnet", Endpoints: local.SubnetIds, Name: name + "-local"}); err != nil {
+		return end, err
+	}
+	if end.PeerEndpointGroup, err = s.EndpointGroups().Create(ctx, &entity.CreateEndpointGroupOpts{
+		Type: "cidr", Endpoints: peerCidrs, Name: name + "-peer"}); err != nil {
+		return end, err
+	}
+	return end, nil
+}
+
+// connectVPNEnd creates the site connection of local towards the external
+// address of peer's VPN service.
+func (s *Controller) connectVPNEnd(ctx context.Context, name string, vpn SiteToSiteVPN, local, peer VPNEnd, psk string) (entity.IpsecSiteConnection, error) {
+	peerAddress := peer.VpnService.ExternalV4Ip
+	if peerAddress == "" {
+		service, err := s.VpnServices().Get(ctx, peer.VpnService.Id)
+		if err != nil {
+			return entity.IpsecSiteConnection{}, err
+		}
+		peerAddress = service.ExternalV4Ip
+	}
+	if peerAddress == "" {
+		return entity.IpsecSiteConnection{}, fmt.Errorf(
+			"vpn service %s has no external address, is the router gateway set", peer.VpnService.Id)
+	}
+	return s.IpsecSiteConnections().Create(ctx, &entity.CreateIpsecSiteConnectionOpts{
+		Name: name,
+		VpnserviceID: local.VpnService.Id,
+		IkepolicyID: vpn.IkePolicy.Id,
+		IpsecpolicyID: vpn.IpsecPolicy.Id,
+		LocalEPGroupID: local.LocalEndpointGroup.Id,
+		PeerEPGroupID: local.PeerEndpointGroup.Id,
+		PeerAddress: peerAddress,
+		PeerID: peerAddress,
+		PSK: psk,
+	})
+}
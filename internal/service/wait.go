@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// waitFor polls check every interval until it reports done, returns an
+// error, timeout elapses or ctx is done.
+func waitFor(ctx context.Context, description string, interval, timeout time.Duration, check func() (bool, error)) error {
+	deadline := time.After(timeout)
+	for {
+		done, err := check()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline:
+			return fmt.Errorf("timeout after %s waiting for %s", timeout, description)
+		case <-time.After(interval):
+		}
+	}
+}
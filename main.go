@@ -1,19 +1,26 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"go-openstackclient/configs"
 	"go-openstackclient/internal/service"
+	"log"
 )
 
-func init() {
-	configs.Viper()
-}
-
 func main() {
+	configPath := flag.String("config", "", "path to clouds.yaml or the legacy openstack.yaml")
+	flag.Parse()
+	if err := configs.Init(*configPath); err != nil {
+		log.Fatalln(err)
+	}
+
 	//service.CreateNetworkHelper()
-	//service.CreateInstanceHelper("599771ab-5682-49f5-a291-cf674aad91fb", "sdn_test")
+	//service.CreateInstanceHelper("599771ab-5682-49f5-a291-cf674aad91fb", "sdn_test", "")
 
 
-	service.ListNetworkHelper()
+	if _, err := service.ListNetworkHelper(context.Background()); err != nil {
+		log.Fatalln(err)
+	}
 }
 